@@ -3,8 +3,10 @@ module github.com/ravendb/terraform-provider-ravendb
 go 1.16
 
 require (
+	github.com/aws/aws-sdk-go v1.40.56
 	github.com/gruntwork-io/terratest v0.38.2
 	github.com/hashicorp/go-multierror v1.1.1
+	github.com/hashicorp/go-version v1.3.0
 	github.com/hashicorp/terraform-plugin-sdk/v2 v2.8.0
 	github.com/ravendb/ravendb-go-client v0.0.0-20211027083244-e99e1a2e8a42
 	golang.org/x/crypto v0.0.0-20210921155107-089bfa567519
@@ -0,0 +1,171 @@
+package operations
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ravendb/ravendb-go-client"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type LocalSettings struct {
+	Disabled   bool   `json:"Disabled"`
+	FolderPath string `json:"FolderPath"`
+}
+
+type S3Settings struct {
+	Disabled         bool   `json:"Disabled"`
+	AwsAccessKey     string `json:"AwsAccessKey"`
+	AwsSecretKey     string `json:"AwsSecretKey"`
+	AwsSessionToken  string `json:"AwsSessionToken,omitempty"`
+	AwsRegionName    string `json:"AwsRegionName"`
+	BucketName       string `json:"BucketName"`
+	RemoteFolderName string `json:"RemoteFolderName,omitempty"`
+	CustomServerUrl  string `json:"CustomServerUrl,omitempty"`
+	ForcePathStyle   bool   `json:"ForcePathStyle"`
+}
+
+type RetentionPolicy struct {
+	Disabled               bool    `json:"Disabled"`
+	MinimumBackupAgeToKeep *string `json:"MinimumBackupAgeToKeep"`
+}
+
+type BackupEncryptionSettings struct {
+	Key            string `json:"Key,omitempty"`
+	EncryptionMode string `json:"EncryptionMode"`
+}
+
+// BackupConfiguration describes what kind of backup is taken and where it is written to.
+type BackupConfiguration struct {
+	BackupType               string                    `json:"BackupType"`
+	LocalSettings            *LocalSettings            `json:"LocalSettings,omitempty"`
+	S3Settings               *S3Settings               `json:"S3Settings,omitempty"`
+	BackupEncryptionSettings *BackupEncryptionSettings `json:"BackupEncryptionSettings,omitempty"`
+}
+
+type PeriodicBackupConfiguration struct {
+	BackupConfiguration
+	TaskId                     int64            `json:"TaskId"`
+	Name                       string           `json:"Name"`
+	Disabled                   bool             `json:"Disabled"`
+	MentorNode                 string           `json:"MentorNode,omitempty"`
+	FullBackupFrequency        string           `json:"FullBackupFrequency"`
+	IncrementalBackupFrequency string           `json:"IncrementalBackupFrequency,omitempty"`
+	RetentionPolicy            *RetentionPolicy `json:"RetentionPolicy,omitempty"`
+}
+
+// PeriodicBackups returns the backup tasks stored in the database record.
+func (r DatabaseRecord) PeriodicBackups() ([]PeriodicBackupConfiguration, error) {
+	var backups []PeriodicBackupConfiguration
+	if r["PeriodicBackups"] == nil {
+		return backups, nil
+	}
+	raw, err := json.Marshal(r["PeriodicBackups"])
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(raw, &backups)
+	return backups, err
+}
+
+// OperationPutPeriodicBackup creates the backup task of Database, or updates it when Configuration.TaskId is set.
+type OperationPutPeriodicBackup struct {
+	Database      string
+	Configuration PeriodicBackupConfiguration
+	Result        struct {
+		TaskId int64 `json:"TaskId"`
+	}
+}
+
+func (operation *OperationPutPeriodicBackup) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &putPeriodicBackup{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeObject,
+		},
+		parent: operation,
+	}, nil
+}
+
+type putPeriodicBackup struct {
+	ravendb.RavenCommandBase
+	parent *OperationPutPeriodicBackup
+}
+
+func (c *putPeriodicBackup) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	body, err := json.Marshal(c.parent.Configuration)
+	if err != nil {
+		return nil, err
+	}
+	return http.NewRequest(http.MethodPost, node.URL+"/databases/"+c.parent.Database+"/admin/periodic-backup", bytes.NewReader(body))
+}
+
+func (c *putPeriodicBackup) SetResponse(response []byte, fromCache bool) error {
+	return json.Unmarshal(response, &c.parent.Result)
+}
+
+// OperationDeleteOngoingTask deletes the ongoing task TaskId of Database. TaskType is the kind of the task,
+// e.g. Backup.
+type OperationDeleteOngoingTask struct {
+	Database string
+	TaskId   int64
+	TaskType string
+}
+
+func (operation *OperationDeleteOngoingTask) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &deleteOngoingTask{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeEmpty,
+		},
+		parent: operation,
+	}, nil
+}
+
+type deleteOngoingTask struct {
+	ravendb.RavenCommandBase
+	parent *OperationDeleteOngoingTask
+}
+
+func (c *deleteOngoingTask) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	query := url.Values{
+		"id":   {strconv.FormatInt(c.parent.TaskId, 10)},
+		"type": {c.parent.TaskType},
+	}
+	return http.NewRequest(http.MethodDelete, node.URL+"/databases/"+c.parent.Database+"/admin/tasks?"+query.Encode(), nil)
+}
+
+// OperationBackup starts a one-time backup of Database. Result holds the id of the operation to wait for.
+type OperationBackup struct {
+	Database      string
+	Configuration BackupConfiguration
+	Result        struct {
+		OperationId     int64  `json:"OperationId"`
+		ResponsibleNode string `json:"ResponsibleNode"`
+	}
+}
+
+func (operation *OperationBackup) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &backup{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeObject,
+		},
+		parent: operation,
+	}, nil
+}
+
+type backup struct {
+	ravendb.RavenCommandBase
+	parent *OperationBackup
+}
+
+func (c *backup) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	body, err := json.Marshal(c.parent.Configuration)
+	if err != nil {
+		return nil, err
+	}
+	return http.NewRequest(http.MethodPost, node.URL+"/databases/"+c.parent.Database+"/admin/backup", bytes.NewReader(body))
+}
+
+func (c *backup) SetResponse(response []byte, fromCache bool) error {
+	return json.Unmarshal(response, &c.parent.Result)
+}
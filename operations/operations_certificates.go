@@ -0,0 +1,126 @@
+package operations
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/ravendb/ravendb-go-client"
+	"net/http"
+	"net/url"
+)
+
+// ClientCertificate is a client certificate registered with the cluster. Certificate is the DER encoded public
+// certificate, Permissions maps database names to Admin, ReadWrite or Read.
+type ClientCertificate struct {
+	Name              string
+	Certificate       []byte
+	Thumbprint        string
+	SecurityClearance string
+	Permissions       map[string]string
+}
+
+// OperationGetCertificate reads the certificate registered with Thumbprint. Result is nil when it is not registered.
+type OperationGetCertificate struct {
+	Thumbprint string
+	Result     *struct {
+		Name              string            `json:"Name"`
+		SecurityClearance string            `json:"SecurityClearance"`
+		Permissions       map[string]string `json:"Permissions"`
+	}
+}
+
+func (operation *OperationGetCertificate) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &getCertificate{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeObject,
+		},
+		parent: operation,
+	}, nil
+}
+
+type getCertificate struct {
+	ravendb.RavenCommandBase
+	parent *OperationGetCertificate
+}
+
+func (c *getCertificate) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	return http.NewRequest(http.MethodGet, node.URL+"/certificates?thumbprint="+url.QueryEscape(c.parent.Thumbprint), nil)
+}
+
+func (c *getCertificate) SetResponse(response []byte, fromCache bool) error {
+	var result struct {
+		Results []json.RawMessage `json:"Results"`
+	}
+	if len(response) == 0 {
+		return nil
+	}
+	err := json.Unmarshal(response, &result)
+	if err != nil || len(result.Results) == 0 {
+		return err
+	}
+	return json.Unmarshal(result.Results[0], &c.parent.Result)
+}
+
+// OperationPutClientCertificate registers a client certificate with the cluster.
+type OperationPutClientCertificate struct {
+	Certificate ClientCertificate
+}
+
+func (operation *OperationPutClientCertificate) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &putClientCertificate{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeEmpty,
+		},
+		parent: operation,
+	}, nil
+}
+
+type putClientCertificate struct {
+	ravendb.RavenCommandBase
+	parent *OperationPutClientCertificate
+}
+
+func (c *putClientCertificate) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"Name":              c.parent.Certificate.Name,
+		"Certificate":       base64.StdEncoding.EncodeToString(c.parent.Certificate.Certificate),
+		"SecurityClearance": c.parent.Certificate.SecurityClearance,
+		"Permissions":       c.parent.Certificate.Permissions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return http.NewRequest(http.MethodPut, node.URL+"/admin/certificates", bytes.NewReader(body))
+}
+
+// OperationEditClientCertificate updates the name, clearance and permissions of a registered client certificate.
+type OperationEditClientCertificate struct {
+	Certificate ClientCertificate
+}
+
+func (operation *OperationEditClientCertificate) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &editClientCertificate{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeEmpty,
+		},
+		parent: operation,
+	}, nil
+}
+
+type editClientCertificate struct {
+	ravendb.RavenCommandBase
+	parent *OperationEditClientCertificate
+}
+
+func (c *editClientCertificate) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"Thumbprint":        c.parent.Certificate.Thumbprint,
+		"Name":              c.parent.Certificate.Name,
+		"SecurityClearance": c.parent.Certificate.SecurityClearance,
+		"Permissions":       c.parent.Certificate.Permissions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return http.NewRequest(http.MethodPost, node.URL+"/admin/certificates/edit", bytes.NewReader(body))
+}
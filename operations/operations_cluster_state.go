@@ -0,0 +1,53 @@
+package operations
+
+import (
+	"encoding/json"
+	"github.com/ravendb/ravendb-go-client"
+	"net/http"
+)
+
+type ClusterNodeStatus struct {
+	Connected    bool   `json:"Connected"`
+	ErrorDetails string `json:"ErrorDetails"`
+}
+
+// ClusterState is the cluster topology as seen by a node: the nodes by tag in every role, the leader and the
+// connection status of the other nodes. Status is only reported by the leader.
+type ClusterState struct {
+	Leader   string `json:"Leader"`
+	NodeTag  string `json:"NodeTag"`
+	Topology struct {
+		TopologyId  string            `json:"TopologyId"`
+		Members     map[string]string `json:"Members"`
+		Promotables map[string]string `json:"Promotables"`
+		Watchers    map[string]string `json:"Watchers"`
+	} `json:"Topology"`
+	Status map[string]ClusterNodeStatus `json:"Status"`
+}
+
+// OperationGetClusterState reads the cluster topology together with the leader and the status of the nodes.
+type OperationGetClusterState struct {
+	Result ClusterState
+}
+
+func (operation *OperationGetClusterState) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &getClusterState{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeObject,
+		},
+		parent: operation,
+	}, nil
+}
+
+type getClusterState struct {
+	ravendb.RavenCommandBase
+	parent *OperationGetClusterState
+}
+
+func (c *getClusterState) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	return http.NewRequest(http.MethodGet, node.URL+"/cluster/topology", nil)
+}
+
+func (c *getClusterState) SetResponse(response []byte, fromCache bool) error {
+	return json.Unmarshal(response, &c.parent.Result)
+}
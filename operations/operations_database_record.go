@@ -0,0 +1,91 @@
+package operations
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ravendb/ravendb-go-client"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// DatabaseRecord holds the raw database record, so it can be written back without dropping the fields the
+// provider doesn't manage.
+type DatabaseRecord map[string]interface{}
+
+// Etag is the raft index the record was last modified at, used to reject concurrent updates.
+func (r DatabaseRecord) Etag() int64 {
+	etag, _ := r["Etag"].(float64)
+	return int64(etag)
+}
+
+// OperationGetDatabaseRecord reads the record of Database. Result is nil when the database does not exist.
+type OperationGetDatabaseRecord struct {
+	Database string
+	Result   DatabaseRecord
+}
+
+func (operation *OperationGetDatabaseRecord) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &getDatabaseRecord{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeObject,
+		},
+		parent: operation,
+	}, nil
+}
+
+type getDatabaseRecord struct {
+	ravendb.RavenCommandBase
+	parent *OperationGetDatabaseRecord
+}
+
+func (c *getDatabaseRecord) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	return http.NewRequest(http.MethodGet, node.URL+"/admin/databases?name="+url.QueryEscape(c.parent.Database), nil)
+}
+
+func (c *getDatabaseRecord) SetResponse(response []byte, fromCache bool) error {
+	if len(response) == 0 {
+		c.parent.Result = nil
+		return nil
+	}
+	return json.Unmarshal(response, &c.parent.Result)
+}
+
+// OperationUpdateDatabaseRecord replaces the record of an existing database. The update is rejected when the
+// record was modified since it was read.
+type OperationUpdateDatabaseRecord struct {
+	Record DatabaseRecord
+}
+
+func (operation *OperationUpdateDatabaseRecord) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &updateDatabaseRecord{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeObject,
+		},
+		parent: operation,
+	}, nil
+}
+
+type updateDatabaseRecord struct {
+	ravendb.RavenCommandBase
+	parent *OperationUpdateDatabaseRecord
+}
+
+func (c *updateDatabaseRecord) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	body, err := json.Marshal(c.parent.Record)
+	if err != nil {
+		return nil, err
+	}
+	name, _ := c.parent.Record["DatabaseName"].(string)
+	request, err := http.NewRequest(http.MethodPut, node.URL+"/admin/databases?name="+url.QueryEscape(name), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	// the server takes the raft index of the record it replaces from the ETag header, as for CreateDatabaseOperation
+	request.Header.Set("ETag", "\""+strconv.FormatInt(c.parent.Record.Etag(), 10)+"\"")
+	return request, nil
+}
+
+func (c *updateDatabaseRecord) SetResponse(response []byte, fromCache bool) error {
+	return nil
+}
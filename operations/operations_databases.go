@@ -0,0 +1,67 @@
+package operations
+
+import (
+	"encoding/json"
+	"github.com/ravendb/ravendb-go-client"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+type DatabaseTopologyNode struct {
+	NodeTag string `json:"NodeTag"`
+	NodeUrl string `json:"NodeUrl"`
+}
+
+type DatabaseInfo struct {
+	Name              string `json:"Name"`
+	Disabled          bool   `json:"Disabled"`
+	IsEncrypted       bool   `json:"IsEncrypted"`
+	ReplicationFactor int    `json:"ReplicationFactor"`
+	IndexesCount      int    `json:"IndexesCount"`
+	LoadError         string `json:"LoadError"`
+	NodesTopology     struct {
+		Members     []DatabaseTopologyNode `json:"Members"`
+		Promotables []DatabaseTopologyNode `json:"Promotables"`
+		Rehabs      []DatabaseTopologyNode `json:"Rehabs"`
+		Status      map[string]struct {
+			LastStatus string `json:"LastStatus"`
+			LastError  string `json:"LastError"`
+		} `json:"Status"`
+	} `json:"NodesTopology"`
+}
+
+// OperationGetDatabases lists the databases of the cluster, as seen by the node the request is sent to.
+type OperationGetDatabases struct {
+	Result []DatabaseInfo
+}
+
+func (operation *OperationGetDatabases) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &getDatabases{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeObject,
+		},
+		parent: operation,
+	}, nil
+}
+
+type getDatabases struct {
+	ravendb.RavenCommandBase
+	parent *OperationGetDatabases
+}
+
+func (c *getDatabases) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	return http.NewRequest(http.MethodGet, node.URL+"/databases?start=0&pageSize="+strconv.Itoa(math.MaxInt32), nil)
+}
+
+func (c *getDatabases) SetResponse(response []byte, fromCache bool) error {
+	var result struct {
+		Databases []DatabaseInfo `json:"Databases"`
+	}
+	err := json.Unmarshal(response, &result)
+	if err != nil {
+		return err
+	}
+	c.parent.Result = result.Databases
+	return nil
+}
@@ -0,0 +1,51 @@
+package operations
+
+import (
+	"github.com/ravendb/ravendb-go-client"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// OperationDownloadLogs downloads the server log files as a zip archive. From and To are optional.
+type OperationDownloadLogs struct {
+	From   time.Time
+	To     time.Time
+	Result []byte
+}
+
+func (operation *OperationDownloadLogs) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &downloadLogs{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeRaw,
+		},
+		parent: operation,
+	}, nil
+}
+
+type downloadLogs struct {
+	ravendb.RavenCommandBase
+	parent *OperationDownloadLogs
+}
+
+func (c *downloadLogs) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	query := url.Values{}
+	if !c.parent.From.IsZero() {
+		query.Set("from", c.parent.From.UTC().Format(time.RFC3339))
+	}
+	if !c.parent.To.IsZero() {
+		query.Set("to", c.parent.To.UTC().Format(time.RFC3339))
+	}
+	return http.NewRequest(http.MethodGet, node.URL+"/admin/logs/download?"+query.Encode(), nil)
+}
+
+func (c *downloadLogs) SetResponseRaw(response *http.Response, stream io.Reader) error {
+	body, err := ioutil.ReadAll(stream)
+	if err != nil {
+		return err
+	}
+	c.parent.Result = body
+	return nil
+}
@@ -0,0 +1,228 @@
+package operations
+
+import (
+	"encoding/json"
+	"github.com/ravendb/ravendb-go-client"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// OperationGetIndex reads the definition of the index Name of Database. Result is nil when the index does not exist.
+type OperationGetIndex struct {
+	Database string
+	Name     string
+	Result   *IndexDefinition
+}
+
+func (operation *OperationGetIndex) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &getIndex{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeObject,
+		},
+		parent: operation,
+	}, nil
+}
+
+type getIndex struct {
+	ravendb.RavenCommandBase
+	parent *OperationGetIndex
+}
+
+func (c *getIndex) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	return http.NewRequest(http.MethodGet, node.URL+"/databases/"+c.parent.Database+"/indexes?name="+url.QueryEscape(c.parent.Name), nil)
+}
+
+func (c *getIndex) SetResponse(response []byte, fromCache bool) error {
+	c.parent.Result = nil
+	if len(response) == 0 {
+		return nil
+	}
+	var result struct {
+		Results []IndexDefinition `json:"Results"`
+	}
+	err := json.Unmarshal(response, &result)
+	if err != nil {
+		return err
+	}
+	if len(result.Results) > 0 {
+		c.parent.Result = &result.Results[0]
+	}
+	return nil
+}
+
+// OperationGetIndexes reads the definitions of all the indexes of Database.
+type OperationGetIndexes struct {
+	Database string
+	Result   []IndexDefinition
+}
+
+func (operation *OperationGetIndexes) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &getIndexes{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeObject,
+		},
+		parent: operation,
+	}, nil
+}
+
+type getIndexes struct {
+	ravendb.RavenCommandBase
+	parent *OperationGetIndexes
+}
+
+func (c *getIndexes) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	return http.NewRequest(http.MethodGet, node.URL+"/databases/"+c.parent.Database+"/indexes?start=0&pageSize="+strconv.Itoa(math.MaxInt32), nil)
+}
+
+func (c *getIndexes) SetResponse(response []byte, fromCache bool) error {
+	var result struct {
+		Results []IndexDefinition `json:"Results"`
+	}
+	err := json.Unmarshal(response, &result)
+	if err != nil {
+		return err
+	}
+	c.parent.Result = result.Results
+	return nil
+}
+
+type IndexErrors struct {
+	Name   string       `json:"Name"`
+	Errors []IndexError `json:"Errors"`
+}
+
+type IndexError struct {
+	Timestamp string `json:"Timestamp"`
+	Document  string `json:"Document"`
+	Action    string `json:"Action"`
+	Error     string `json:"Error"`
+}
+
+// OperationGetIndexErrors reads the indexing errors of all the indexes of Database, or only of Names when set.
+type OperationGetIndexErrors struct {
+	Database string
+	Names    []string
+	Result   []IndexErrors
+}
+
+func (operation *OperationGetIndexErrors) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &getIndexErrors{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeObject,
+		},
+		parent: operation,
+	}, nil
+}
+
+type getIndexErrors struct {
+	ravendb.RavenCommandBase
+	parent *OperationGetIndexErrors
+}
+
+func (c *getIndexErrors) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	query := url.Values{"name": c.parent.Names}
+	return http.NewRequest(http.MethodGet, node.URL+"/databases/"+c.parent.Database+"/indexes/errors?"+query.Encode(), nil)
+}
+
+func (c *getIndexErrors) SetResponse(response []byte, fromCache bool) error {
+	var result struct {
+		Results []IndexErrors `json:"Results"`
+	}
+	err := json.Unmarshal(response, &result)
+	if err != nil {
+		return err
+	}
+	c.parent.Result = result.Results
+	return nil
+}
+
+// OperationResetIndex drops the results of the index Name of Database and indexes all the documents again.
+type OperationResetIndex struct {
+	Database string
+	Name     string
+}
+
+func (operation *OperationResetIndex) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &resetIndex{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeEmpty,
+		},
+		parent: operation,
+	}, nil
+}
+
+type resetIndex struct {
+	ravendb.RavenCommandBase
+	parent *OperationResetIndex
+}
+
+func (c *resetIndex) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	return http.NewRequest("RESET", node.URL+"/databases/"+c.parent.Database+"/indexes?name="+url.QueryEscape(c.parent.Name), nil)
+}
+
+// IndexReplacementPrefix prefixes the name of the side by side index that replaces an updated index once it is not stale.
+const IndexReplacementPrefix = "ReplacementOf/"
+
+// OperationReplaceIndex swaps in the side by side replacement of the index Name of Database right away, even if it is stale.
+type OperationReplaceIndex struct {
+	Database string
+	Name     string
+}
+
+func (operation *OperationReplaceIndex) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &replaceIndex{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeEmpty,
+		},
+		parent: operation,
+	}, nil
+}
+
+type replaceIndex struct {
+	ravendb.RavenCommandBase
+	parent *OperationReplaceIndex
+}
+
+func (c *replaceIndex) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	return http.NewRequest(http.MethodPost, node.URL+"/databases/"+c.parent.Database+"/indexes/replace?name="+url.QueryEscape(c.parent.Name), nil)
+}
+
+// Index actions that change the state of an index. Enable and disable apply to the whole cluster, while start and
+// stop pause and resume the indexing on the node the request is sent to only.
+const (
+	IndexActionEnable  = "enable"
+	IndexActionDisable = "disable"
+	IndexActionStart   = "start"
+	IndexActionStop    = "stop"
+)
+
+// OperationIndexAction runs Action, one of the IndexAction constants, on the index Name of Database.
+type OperationIndexAction struct {
+	Database string
+	Name     string
+	Action   string
+}
+
+func (operation *OperationIndexAction) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &indexAction{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeEmpty,
+		},
+		parent: operation,
+	}, nil
+}
+
+type indexAction struct {
+	ravendb.RavenCommandBase
+	parent *OperationIndexAction
+}
+
+func (c *indexAction) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	query := url.Values{"name": {c.parent.Name}}
+	if c.parent.Action == IndexActionEnable || c.parent.Action == IndexActionDisable {
+		query.Set("clusterWide", "true")
+	}
+	return http.NewRequest(http.MethodPost, node.URL+"/databases/"+c.parent.Database+"/admin/indexes/"+c.parent.Action+"?"+query.Encode(), nil)
+}
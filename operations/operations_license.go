@@ -0,0 +1,73 @@
+package operations
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ravendb/ravendb-go-client"
+	"net/http"
+)
+
+// OperationActivateLicense activates License, the license JSON, on the cluster without contacting the RavenDB
+// license server.
+type OperationActivateLicense struct {
+	License []byte
+}
+
+func (operation *OperationActivateLicense) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &activateLicense{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeEmpty,
+		},
+		parent: operation,
+	}, nil
+}
+
+type activateLicense struct {
+	ravendb.RavenCommandBase
+	parent *OperationActivateLicense
+}
+
+func (c *activateLicense) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	return http.NewRequest(http.MethodPost, node.URL+"/admin/license/activate", bytes.NewReader(c.parent.License))
+}
+
+type NodeLicenseDetails struct {
+	UtilizedCores    int  `json:"UtilizedCores"`
+	MaxUtilizedCores *int `json:"MaxUtilizedCores"`
+	NumberOfCores    int  `json:"NumberOfCores"`
+}
+
+// OperationGetNodeLicenseDetails reads the license cores assigned to every node of the cluster, by node tag.
+type OperationGetNodeLicenseDetails struct {
+	Result map[string]NodeLicenseDetails
+}
+
+func (operation *OperationGetNodeLicenseDetails) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &getNodeLicenseDetails{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeObject,
+		},
+		parent: operation,
+	}, nil
+}
+
+type getNodeLicenseDetails struct {
+	ravendb.RavenCommandBase
+	parent *OperationGetNodeLicenseDetails
+}
+
+func (c *getNodeLicenseDetails) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	return http.NewRequest(http.MethodGet, node.URL+"/cluster/topology", nil)
+}
+
+func (c *getNodeLicenseDetails) SetResponse(response []byte, fromCache bool) error {
+	var result struct {
+		NodeLicenseDetails map[string]NodeLicenseDetails `json:"NodeLicenseDetails"`
+	}
+	err := json.Unmarshal(response, &result)
+	if err != nil {
+		return err
+	}
+	c.parent.Result = result.NodeLicenseDetails
+	return nil
+}
@@ -0,0 +1,88 @@
+package operations
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ravendb/ravendb-go-client"
+	"net/http"
+	"strconv"
+)
+
+type OperationIdResult struct {
+	OperationId int64 `json:"OperationId"`
+}
+
+// OperationMigrate starts a migration of an external source into Database. Path selects the migrator
+// ("ravendb" for another RavenDB server, empty for the Raven.Migrator based sources) and Configuration
+// is sent as-is as the request body.
+type OperationMigrate struct {
+	Database      string
+	Path          string
+	Configuration map[string]interface{}
+	Result        OperationIdResult
+}
+
+func (operation *OperationMigrate) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &migrate{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeObject,
+		},
+		parent: operation,
+	}, nil
+}
+
+type migrate struct {
+	ravendb.RavenCommandBase
+	parent *OperationMigrate
+}
+
+func (c *migrate) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	body, err := json.Marshal(c.parent.Configuration)
+	if err != nil {
+		return nil, err
+	}
+	url := node.URL + "/databases/" + c.parent.Database + "/admin/smuggler/migrate"
+	if c.parent.Path != "" {
+		url += "/" + c.parent.Path
+	}
+	return http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+}
+
+func (c *migrate) SetResponse(response []byte, fromCache bool) error {
+	return json.Unmarshal(response, &c.parent.Result)
+}
+
+// OperationGetOperationState reads the state of a long running operation of Database.
+type OperationGetOperationState struct {
+	Database string
+	Id       int64
+	Result   OperationState
+}
+
+type OperationState struct {
+	Status string          `json:"Status"`
+	Result json.RawMessage `json:"Result"`
+}
+
+func (operation *OperationGetOperationState) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &getOperationState{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeObject,
+		},
+		parent: operation,
+	}, nil
+}
+
+type getOperationState struct {
+	ravendb.RavenCommandBase
+	parent *OperationGetOperationState
+}
+
+func (c *getOperationState) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	url := node.URL + "/databases/" + c.parent.Database + "/operations/state?id=" + strconv.FormatInt(c.parent.Id, 10)
+	return http.NewRequest(http.MethodGet, url, nil)
+}
+
+func (c *getOperationState) SetResponse(response []byte, fromCache bool) error {
+	return json.Unmarshal(response, &c.parent.Result)
+}
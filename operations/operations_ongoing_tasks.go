@@ -0,0 +1,57 @@
+package operations
+
+import (
+	"encoding/json"
+	"github.com/ravendb/ravendb-go-client"
+	"net/http"
+)
+
+type OngoingTask struct {
+	TaskId               int64  `json:"TaskId"`
+	TaskType             string `json:"TaskType"`
+	TaskName             string `json:"TaskName"`
+	TaskState            string `json:"TaskState"`
+	TaskConnectionStatus string `json:"TaskConnectionStatus"`
+	MentorNode           string `json:"MentorNode"`
+	Error                string `json:"Error"`
+	ResponsibleNode      struct {
+		NodeTag string `json:"NodeTag"`
+		NodeUrl string `json:"NodeUrl"`
+	} `json:"ResponsibleNode"`
+}
+
+// OperationGetOngoingTasks lists the ongoing tasks (backups, ETLs, replications, subscriptions) of Database.
+type OperationGetOngoingTasks struct {
+	Database string
+	Result   []OngoingTask
+}
+
+func (operation *OperationGetOngoingTasks) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &getOngoingTasks{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeObject,
+		},
+		parent: operation,
+	}, nil
+}
+
+type getOngoingTasks struct {
+	ravendb.RavenCommandBase
+	parent *OperationGetOngoingTasks
+}
+
+func (c *getOngoingTasks) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	return http.NewRequest(http.MethodGet, node.URL+"/databases/"+c.parent.Database+"/tasks", nil)
+}
+
+func (c *getOngoingTasks) SetResponse(response []byte, fromCache bool) error {
+	var result struct {
+		OngoingTasksList []OngoingTask `json:"OngoingTasksList"`
+	}
+	err := json.Unmarshal(response, &result)
+	if err != nil {
+		return err
+	}
+	c.parent.Result = result.OngoingTasksList
+	return nil
+}
@@ -0,0 +1,42 @@
+package operations
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ravendb/ravendb-go-client"
+	"net/http"
+)
+
+// OperationPutPostgreSqlUser adds a user that can connect to Database through the PostgreSQL protocol.
+type OperationPutPostgreSqlUser struct {
+	Database string `json:"-"`
+	Username string `json:"Username"`
+	Password string `json:"Password"`
+}
+
+func (operation *OperationPutPostgreSqlUser) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &putPostgreSqlUser{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeEmpty,
+		},
+		parent: operation,
+	}, nil
+}
+
+type putPostgreSqlUser struct {
+	ravendb.RavenCommandBase
+	parent *OperationPutPostgreSqlUser
+}
+
+func (c *putPostgreSqlUser) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	body, err := json.Marshal(c.parent)
+	if err != nil {
+		return nil, err
+	}
+	url := node.URL + "/databases/" + c.parent.Database + "/admin/integrations/postgresql/user"
+	return http.NewRequest(http.MethodPut, url, bytes.NewReader(body))
+}
+
+func (c *putPostgreSqlUser) SetResponse(response []byte, fromCache bool) error {
+	return nil
+}
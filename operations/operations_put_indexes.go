@@ -0,0 +1,67 @@
+package operations
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ravendb/ravendb-go-client"
+	"net/http"
+)
+
+type IndexDefinition struct {
+	Name                                         string            `json:"Name"`
+	Type                                         string            `json:"Type,omitempty"`
+	Maps                                         []string          `json:"Maps"`
+	Reduce                                       string            `json:"Reduce,omitempty"`
+	Configuration                                map[string]string `json:"Configuration,omitempty"`
+	OutputReduceToCollection                     string            `json:"OutputReduceToCollection,omitempty"`
+	PatternForOutputReduceToCollectionReferences string            `json:"PatternForOutputReduceToCollectionReferences,omitempty"`
+	PatternReferencesCollectionName              string            `json:"PatternReferencesCollectionName,omitempty"`
+}
+
+type PutIndexResult struct {
+	Index            string `json:"Index"`
+	RaftCommandIndex int64  `json:"RaftCommandIndex"`
+}
+
+// OperationPutIndexes creates or updates the given index definitions of Database.
+type OperationPutIndexes struct {
+	Database string
+	Indexes  []IndexDefinition
+	Result   []PutIndexResult
+}
+
+func (operation *OperationPutIndexes) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &putIndexes{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeObject,
+		},
+		parent: operation,
+	}, nil
+}
+
+type putIndexes struct {
+	ravendb.RavenCommandBase
+	parent *OperationPutIndexes
+}
+
+func (c *putIndexes) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"Indexes": c.parent.Indexes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return http.NewRequest(http.MethodPut, node.URL+"/databases/"+c.parent.Database+"/admin/indexes", bytes.NewReader(body))
+}
+
+func (c *putIndexes) SetResponse(response []byte, fromCache bool) error {
+	var result struct {
+		Results []PutIndexResult `json:"Results"`
+	}
+	err := json.Unmarshal(response, &result)
+	if err != nil {
+		return err
+	}
+	c.parent.Result = result.Results
+	return nil
+}
@@ -0,0 +1,177 @@
+package operations
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ravendb/ravendb-go-client"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type KafkaConnectionSettings struct {
+	BootstrapServers    string            `json:"BootstrapServers"`
+	ConnectionOptions   map[string]string `json:"ConnectionOptions,omitempty"`
+	UseRavenCertificate bool              `json:"UseRavenCertificate"`
+}
+
+type RabbitMqConnectionSettings struct {
+	ConnectionString string `json:"ConnectionString"`
+}
+
+// QueueConnectionString is the connection string of a Kafka or RabbitMQ broker, used by queue ETL and sink tasks.
+type QueueConnectionString struct {
+	Name                       string                      `json:"Name"`
+	Type                       string                      `json:"Type"`
+	BrokerType                 string                      `json:"BrokerType"`
+	KafkaConnectionSettings    *KafkaConnectionSettings    `json:"KafkaConnectionSettings,omitempty"`
+	RabbitMqConnectionSettings *RabbitMqConnectionSettings `json:"RabbitMqConnectionSettings,omitempty"`
+}
+
+type QueueSinkScript struct {
+	Name     string   `json:"Name"`
+	Queues   []string `json:"Queues"`
+	Script   string   `json:"Script"`
+	Disabled bool     `json:"Disabled"`
+}
+
+// QueueSinkConfiguration describes a task consuming the queues of a broker into documents with its scripts.
+type QueueSinkConfiguration struct {
+	TaskId               int64             `json:"TaskId"`
+	Name                 string            `json:"Name"`
+	ConnectionStringName string            `json:"ConnectionStringName"`
+	BrokerType           string            `json:"BrokerType"`
+	Scripts              []QueueSinkScript `json:"Scripts"`
+	Disabled             bool              `json:"Disabled"`
+	MentorNode           string            `json:"MentorNode,omitempty"`
+}
+
+// QueueSinks returns the queue sink tasks stored in the database record.
+func (r DatabaseRecord) QueueSinks() ([]QueueSinkConfiguration, error) {
+	var sinks []QueueSinkConfiguration
+	if r["QueueSinks"] == nil {
+		return sinks, nil
+	}
+	raw, err := json.Marshal(r["QueueSinks"])
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(raw, &sinks)
+	return sinks, err
+}
+
+// QueueConnectionStrings returns the queue connection strings stored in the database record, by name.
+func (r DatabaseRecord) QueueConnectionStrings() (map[string]QueueConnectionString, error) {
+	connectionStrings := map[string]QueueConnectionString{}
+	if r["QueueConnectionStrings"] == nil {
+		return connectionStrings, nil
+	}
+	raw, err := json.Marshal(r["QueueConnectionStrings"])
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(raw, &connectionStrings)
+	return connectionStrings, err
+}
+
+// OperationPutQueueConnectionString creates or replaces the connection string of Database named after
+// ConnectionString.Name.
+type OperationPutQueueConnectionString struct {
+	Database         string
+	ConnectionString QueueConnectionString
+}
+
+func (operation *OperationPutQueueConnectionString) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &putQueueConnectionString{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeObject,
+		},
+		parent: operation,
+	}, nil
+}
+
+type putQueueConnectionString struct {
+	ravendb.RavenCommandBase
+	parent *OperationPutQueueConnectionString
+}
+
+func (c *putQueueConnectionString) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	body, err := json.Marshal(c.parent.ConnectionString)
+	if err != nil {
+		return nil, err
+	}
+	return http.NewRequest(http.MethodPut, node.URL+"/databases/"+c.parent.Database+"/admin/connection-strings", bytes.NewReader(body))
+}
+
+func (c *putQueueConnectionString) SetResponse(response []byte, fromCache bool) error {
+	return nil
+}
+
+// OperationRemoveConnectionString deletes the connection string Name of Database. Type is the kind of the
+// connection string, e.g. Queue.
+type OperationRemoveConnectionString struct {
+	Database string
+	Name     string
+	Type     string
+}
+
+func (operation *OperationRemoveConnectionString) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &removeConnectionString{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeEmpty,
+		},
+		parent: operation,
+	}, nil
+}
+
+type removeConnectionString struct {
+	ravendb.RavenCommandBase
+	parent *OperationRemoveConnectionString
+}
+
+func (c *removeConnectionString) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	query := url.Values{
+		"connectionString": {c.parent.Name},
+		"type":             {c.parent.Type},
+	}
+	return http.NewRequest(http.MethodDelete, node.URL+"/databases/"+c.parent.Database+"/admin/connection-strings?"+query.Encode(), nil)
+}
+
+// OperationPutQueueSink creates the queue sink task of Database, or updates it when Configuration.TaskId is set.
+type OperationPutQueueSink struct {
+	Database      string
+	Configuration QueueSinkConfiguration
+	Result        struct {
+		TaskId int64 `json:"TaskId"`
+	}
+}
+
+func (operation *OperationPutQueueSink) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &putQueueSink{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeObject,
+		},
+		parent: operation,
+	}, nil
+}
+
+type putQueueSink struct {
+	ravendb.RavenCommandBase
+	parent *OperationPutQueueSink
+}
+
+func (c *putQueueSink) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	body, err := json.Marshal(c.parent.Configuration)
+	if err != nil {
+		return nil, err
+	}
+	target := node.URL + "/databases/" + c.parent.Database + "/admin/queue-sink"
+	if c.parent.Configuration.TaskId != 0 {
+		target += "?id=" + strconv.FormatInt(c.parent.Configuration.TaskId, 10)
+	}
+	return http.NewRequest(http.MethodPut, target, bytes.NewReader(body))
+}
+
+func (c *putQueueSink) SetResponse(response []byte, fromCache bool) error {
+	return json.Unmarshal(response, &c.parent.Result)
+}
@@ -0,0 +1,105 @@
+package operations
+
+import (
+	"encoding/json"
+	"github.com/ravendb/ravendb-go-client"
+	"net/http"
+)
+
+type LicenseStatus struct {
+	Id             string  `json:"Id"`
+	Type           string  `json:"Type"`
+	MaxCores       int     `json:"MaxCores"`
+	MaxClusterSize int     `json:"MaxClusterSize"`
+	Expired        bool    `json:"Expired"`
+	Expiration     *string `json:"Expiration"`
+}
+
+// OperationGetLicenseStatus reads the status of the license the server is running with, and its limits.
+type OperationGetLicenseStatus struct {
+	Result LicenseStatus
+}
+
+func (operation *OperationGetLicenseStatus) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &getLicenseStatus{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeObject,
+		},
+		parent: operation,
+	}, nil
+}
+
+type getLicenseStatus struct {
+	ravendb.RavenCommandBase
+	parent *OperationGetLicenseStatus
+}
+
+func (c *getLicenseStatus) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	return http.NewRequest(http.MethodGet, node.URL+"/license/status", nil)
+}
+
+func (c *getLicenseStatus) SetResponse(response []byte, fromCache bool) error {
+	return json.Unmarshal(response, &c.parent.Result)
+}
+
+// OperationWhoAmI reads the certificate the server authenticated the client with. Result is empty when the
+// certificate is not known to the server.
+type OperationWhoAmI struct {
+	Result struct {
+		Thumbprint        string `json:"Thumbprint"`
+		SecurityClearance string `json:"SecurityClearance"`
+	}
+}
+
+func (operation *OperationWhoAmI) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &whoAmI{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeObject,
+		},
+		parent: operation,
+	}, nil
+}
+
+type whoAmI struct {
+	ravendb.RavenCommandBase
+	parent *OperationWhoAmI
+}
+
+func (c *whoAmI) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	return http.NewRequest(http.MethodGet, node.URL+"/certificates/whoami", nil)
+}
+
+func (c *whoAmI) SetResponse(response []byte, fromCache bool) error {
+	if len(response) == 0 {
+		return nil
+	}
+	return json.Unmarshal(response, &c.parent.Result)
+}
+
+// OperationGetDatabaseStatistics reads the statistics of Database, which only succeeds once it is loaded.
+type OperationGetDatabaseStatistics struct {
+	Database string
+	Result   map[string]interface{}
+}
+
+func (operation *OperationGetDatabaseStatistics) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &getDatabaseStatistics{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeObject,
+		},
+		parent: operation,
+	}, nil
+}
+
+type getDatabaseStatistics struct {
+	ravendb.RavenCommandBase
+	parent *OperationGetDatabaseStatistics
+}
+
+func (c *getDatabaseStatistics) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	return http.NewRequest(http.MethodGet, node.URL+"/databases/"+c.parent.Database+"/stats", nil)
+}
+
+func (c *getDatabaseStatistics) SetResponse(response []byte, fromCache bool) error {
+	return json.Unmarshal(response, &c.parent.Result)
+}
@@ -0,0 +1,29 @@
+package operations
+
+import (
+	"github.com/ravendb/ravendb-go-client"
+	"net/http"
+)
+
+// OperationCreateSampleData fills Database, which must not contain any documents yet, with the Northwind sample data.
+type OperationCreateSampleData struct {
+	Database string
+}
+
+func (operation *OperationCreateSampleData) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &createSampleData{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeEmpty,
+		},
+		parent: operation,
+	}, nil
+}
+
+type createSampleData struct {
+	ravendb.RavenCommandBase
+	parent *OperationCreateSampleData
+}
+
+func (c *createSampleData) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	return http.NewRequest(http.MethodPost, node.URL+"/databases/"+c.parent.Database+"/studio/sample-data", nil)
+}
@@ -0,0 +1,77 @@
+package operations
+
+import (
+	"github.com/ravendb/ravendb-go-client"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// OperationGenerateSecret generates a new encryption key for a database, as a base64 string.
+type OperationGenerateSecret struct {
+	Result string
+}
+
+func (operation *OperationGenerateSecret) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &generateSecret{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeRaw,
+		},
+		parent: operation,
+	}, nil
+}
+
+type generateSecret struct {
+	ravendb.RavenCommandBase
+	parent *OperationGenerateSecret
+}
+
+func (c *generateSecret) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	return http.NewRequest(http.MethodGet, node.URL+"/admin/secrets/generate", nil)
+}
+
+func (c *generateSecret) SetResponseRaw(response *http.Response, stream io.Reader) error {
+	body, err := ioutil.ReadAll(stream)
+	if err != nil {
+		return err
+	}
+	c.parent.Result = strings.TrimSpace(string(body))
+	return nil
+}
+
+// OperationDistributeSecret stores the encryption key of Database on the given cluster Nodes, which has to be
+// done before an encrypted database is created on them.
+type OperationDistributeSecret struct {
+	Database string
+	Key      string
+	Nodes    []string
+}
+
+func (operation *OperationDistributeSecret) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &distributeSecret{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeEmpty,
+		},
+		parent: operation,
+	}, nil
+}
+
+type distributeSecret struct {
+	ravendb.RavenCommandBase
+	parent *OperationDistributeSecret
+}
+
+func (c *distributeSecret) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	query := url.Values{"name": {c.parent.Database}}
+	for _, tag := range c.parent.Nodes {
+		query.Add("node", tag)
+	}
+	request, err := http.NewRequest(http.MethodPost, node.URL+"/admin/secrets/distribute?"+query.Encode(), strings.NewReader(c.parent.Key))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "text/plain")
+	return request, nil
+}
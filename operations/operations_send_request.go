@@ -0,0 +1,56 @@
+package operations
+
+import (
+	"bytes"
+	"github.com/ravendb/ravendb-go-client"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// OperationSendRequest sends an arbitrary request to the server, for the endpoints no other operation covers.
+// Path is relative to the node url, e.g. /admin/configuration/studio. Result holds the response body.
+type OperationSendRequest struct {
+	Method string
+	Path   string
+	Body   []byte
+	Result []byte
+}
+
+func (operation *OperationSendRequest) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &sendRequest{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeRaw,
+		},
+		parent: operation,
+	}, nil
+}
+
+type sendRequest struct {
+	ravendb.RavenCommandBase
+	parent *OperationSendRequest
+}
+
+func (c *sendRequest) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	var body io.Reader
+	if len(c.parent.Body) > 0 {
+		body = bytes.NewReader(c.parent.Body)
+	}
+	request, err := http.NewRequest(c.parent.Method, node.URL+c.parent.Path, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	return request, nil
+}
+
+func (c *sendRequest) SetResponseRaw(response *http.Response, stream io.Reader) error {
+	body, err := ioutil.ReadAll(stream)
+	if err != nil {
+		return err
+	}
+	c.parent.Result = body
+	return nil
+}
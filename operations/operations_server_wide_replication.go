@@ -0,0 +1,130 @@
+package operations
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ravendb/ravendb-go-client"
+	"net/http"
+	"net/url"
+)
+
+// ServerWideExternalReplication replicates every database of the cluster, except ExcludedDatabases, to the
+// database of the same name on the cluster reached at TopologyDiscoveryUrls.
+type ServerWideExternalReplication struct {
+	Name                  string   `json:"Name"`
+	Disabled              bool     `json:"Disabled"`
+	TopologyDiscoveryUrls []string `json:"TopologyDiscoveryUrls"`
+	MentorNode            string   `json:"MentorNode,omitempty"`
+	DelayReplicationFor   string   `json:"DelayReplicationFor,omitempty"`
+	ExcludedDatabases     []string `json:"ExcludedDatabases"`
+}
+
+// OperationPutServerWideExternalReplication creates the server-wide external replication task named
+// Configuration.Name, or updates it when it exists.
+type OperationPutServerWideExternalReplication struct {
+	Configuration ServerWideExternalReplication
+	Result        struct {
+		Name             string `json:"Name"`
+		RaftCommandIndex int64  `json:"RaftCommandIndex"`
+	}
+}
+
+func (operation *OperationPutServerWideExternalReplication) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &putServerWideExternalReplication{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeObject,
+		},
+		parent: operation,
+	}, nil
+}
+
+type putServerWideExternalReplication struct {
+	ravendb.RavenCommandBase
+	parent *OperationPutServerWideExternalReplication
+}
+
+func (c *putServerWideExternalReplication) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	body, err := json.Marshal(c.parent.Configuration)
+	if err != nil {
+		return nil, err
+	}
+	return http.NewRequest(http.MethodPut, node.URL+"/admin/configuration/server-wide/external-replication", bytes.NewReader(body))
+}
+
+func (c *putServerWideExternalReplication) SetResponse(response []byte, fromCache bool) error {
+	return json.Unmarshal(response, &c.parent.Result)
+}
+
+// OperationGetServerWideExternalReplication reads the server-wide external replication task Name. Result is nil
+// when there is no such task.
+type OperationGetServerWideExternalReplication struct {
+	Name   string
+	Result *ServerWideExternalReplication
+}
+
+func (operation *OperationGetServerWideExternalReplication) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &getServerWideExternalReplication{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeObject,
+		},
+		parent: operation,
+	}, nil
+}
+
+type getServerWideExternalReplication struct {
+	ravendb.RavenCommandBase
+	parent *OperationGetServerWideExternalReplication
+}
+
+func (c *getServerWideExternalReplication) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	query := url.Values{
+		"type": {"Replication"},
+		"name": {c.parent.Name},
+	}
+	return http.NewRequest(http.MethodGet, node.URL+"/admin/configuration/server-wide/tasks?"+query.Encode(), nil)
+}
+
+func (c *getServerWideExternalReplication) SetResponse(response []byte, fromCache bool) error {
+	var result struct {
+		Results []ServerWideExternalReplication `json:"Results"`
+	}
+	err := json.Unmarshal(response, &result)
+	if err != nil {
+		return err
+	}
+	c.parent.Result = nil
+	for i := range result.Results {
+		if result.Results[i].Name == c.parent.Name {
+			c.parent.Result = &result.Results[i]
+		}
+	}
+	return nil
+}
+
+// OperationDeleteServerWideTask deletes the server-wide task Name of TaskType, e.g. Replication.
+type OperationDeleteServerWideTask struct {
+	Name     string
+	TaskType string
+}
+
+func (operation *OperationDeleteServerWideTask) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &deleteServerWideTask{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeEmpty,
+		},
+		parent: operation,
+	}, nil
+}
+
+type deleteServerWideTask struct {
+	ravendb.RavenCommandBase
+	parent *OperationDeleteServerWideTask
+}
+
+func (c *deleteServerWideTask) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	query := url.Values{
+		"type": {c.parent.TaskType},
+		"name": {c.parent.Name},
+	}
+	return http.NewRequest(http.MethodDelete, node.URL+"/admin/configuration/server-wide/task?"+query.Encode(), nil)
+}
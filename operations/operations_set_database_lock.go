@@ -0,0 +1,44 @@
+package operations
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ravendb/ravendb-go-client"
+	"net/http"
+)
+
+const (
+	DatabaseLockModeUnlock              = "Unlock"
+	DatabaseLockModePreventDeletesError = "PreventDeletesError"
+)
+
+// OperationSetDatabasesLock sets the lock mode of Databases, which controls whether they can be deleted.
+type OperationSetDatabasesLock struct {
+	Databases []string
+	Mode      string
+}
+
+func (operation *OperationSetDatabasesLock) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &setDatabasesLock{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeEmpty,
+		},
+		parent: operation,
+	}, nil
+}
+
+type setDatabasesLock struct {
+	ravendb.RavenCommandBase
+	parent *OperationSetDatabasesLock
+}
+
+func (c *setDatabasesLock) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"DatabaseNames": c.parent.Databases,
+		"Mode":          c.parent.Mode,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return http.NewRequest(http.MethodPost, node.URL+"/admin/databases/set-lock", bytes.NewReader(body))
+}
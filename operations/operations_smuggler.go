@@ -0,0 +1,141 @@
+package operations
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ravendb/ravendb-go-client"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// OperationGetNextOperationId reserves the id of the next long running operation of Database.
+type OperationGetNextOperationId struct {
+	Database string
+	Result   struct {
+		Id      int64  `json:"Id"`
+		NodeTag string `json:"NodeTag"`
+	}
+}
+
+func (operation *OperationGetNextOperationId) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &getNextOperationId{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeObject,
+		},
+		parent: operation,
+	}, nil
+}
+
+type getNextOperationId struct {
+	ravendb.RavenCommandBase
+	parent *OperationGetNextOperationId
+}
+
+func (c *getNextOperationId) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	return http.NewRequest(http.MethodGet, node.URL+"/databases/"+c.parent.Database+"/operations/next-operation-id", nil)
+}
+
+func (c *getNextOperationId) SetResponse(response []byte, fromCache bool) error {
+	return json.Unmarshal(response, &c.parent.Result)
+}
+
+// OperationImport imports the .ravendbdump file at Path into Database. Options are sent as-is as the
+// smuggler import options.
+type OperationImport struct {
+	Database    string
+	OperationId int64
+	Path        string
+	Options     map[string]interface{}
+}
+
+func (operation *OperationImport) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &smugglerImport{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeEmpty,
+		},
+		parent: operation,
+	}, nil
+}
+
+type smugglerImport struct {
+	ravendb.RavenCommandBase
+	parent *OperationImport
+}
+
+func (c *smugglerImport) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	options, err := json.Marshal(c.parent.Options)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(c.parent.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	// the dump is streamed into the request body rather than loaded into memory
+	reader, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+	go func() {
+		defer file.Close()
+		err := form.WriteField("importOptions", string(options))
+		if err == nil {
+			var part io.Writer
+			part, err = form.CreateFormFile("file", "import.ravendbdump")
+			if err == nil {
+				_, err = io.Copy(part, file)
+			}
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		writer.CloseWithError(err)
+	}()
+
+	url := node.URL + "/databases/" + c.parent.Database + "/smuggler/import?operationId=" + strconv.FormatInt(c.parent.OperationId, 10)
+	request, err := http.NewRequest(http.MethodPost, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", form.FormDataContentType())
+	return request, nil
+}
+
+// OperationExport exports Database as a .ravendbdump into Output. Options are sent as-is as the
+// smuggler export options.
+type OperationExport struct {
+	Database    string
+	OperationId int64
+	Options     map[string]interface{}
+	Output      io.Writer
+}
+
+func (operation *OperationExport) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &smugglerExport{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeRaw,
+		},
+		parent: operation,
+	}, nil
+}
+
+type smugglerExport struct {
+	ravendb.RavenCommandBase
+	parent *OperationExport
+}
+
+func (c *smugglerExport) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	body, err := json.Marshal(c.parent.Options)
+	if err != nil {
+		return nil, err
+	}
+	url := node.URL + "/databases/" + c.parent.Database + "/smuggler/export?operationId=" + strconv.FormatInt(c.parent.OperationId, 10)
+	return http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+}
+
+func (c *smugglerExport) SetResponseRaw(response *http.Response, stream io.Reader) error {
+	_, err := io.Copy(c.parent.Output, stream)
+	return err
+}
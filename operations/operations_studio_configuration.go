@@ -0,0 +1,85 @@
+package operations
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ravendb/ravendb-go-client"
+	"net/http"
+)
+
+type StudioConfiguration struct {
+	Disabled                 bool   `json:"Disabled"`
+	Environment              string `json:"Environment"`
+	DisableAutoIndexCreation bool   `json:"DisableAutoIndexCreation"`
+}
+
+// OperationPutStudioConfiguration sets the server wide studio configuration, or the one of Database when it is set.
+type OperationPutStudioConfiguration struct {
+	Database      string
+	Configuration StudioConfiguration
+}
+
+func (operation *OperationPutStudioConfiguration) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &putStudioConfiguration{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeEmpty,
+		},
+		parent: operation,
+	}, nil
+}
+
+type putStudioConfiguration struct {
+	ravendb.RavenCommandBase
+	parent *OperationPutStudioConfiguration
+}
+
+func (c *putStudioConfiguration) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	body, err := json.Marshal(c.parent.Configuration)
+	if err != nil {
+		return nil, err
+	}
+	if c.parent.Database == "" {
+		return http.NewRequest(http.MethodPut, node.URL+"/admin/configuration/studio", bytes.NewReader(body))
+	}
+	return http.NewRequest(http.MethodPost, node.URL+"/databases/"+c.parent.Database+"/admin/configuration/studio", bytes.NewReader(body))
+}
+
+func (c *putStudioConfiguration) SetResponse(response []byte, fromCache bool) error {
+	return nil
+}
+
+// OperationGetStudioConfiguration reads the server wide studio configuration, or the one of Database when it is set.
+// Result is nil when no configuration was stored.
+type OperationGetStudioConfiguration struct {
+	Database string
+	Result   *StudioConfiguration
+}
+
+func (operation *OperationGetStudioConfiguration) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &getStudioConfiguration{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeObject,
+		},
+		parent: operation,
+	}, nil
+}
+
+type getStudioConfiguration struct {
+	ravendb.RavenCommandBase
+	parent *OperationGetStudioConfiguration
+}
+
+func (c *getStudioConfiguration) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	if c.parent.Database == "" {
+		return http.NewRequest(http.MethodGet, node.URL+"/configuration/studio", nil)
+	}
+	return http.NewRequest(http.MethodGet, node.URL+"/databases/"+c.parent.Database+"/configuration/studio", nil)
+}
+
+func (c *getStudioConfiguration) SetResponse(response []byte, fromCache bool) error {
+	if len(response) == 0 {
+		c.parent.Result = nil
+		return nil
+	}
+	return json.Unmarshal(response, &c.parent.Result)
+}
@@ -0,0 +1,43 @@
+package operations
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ravendb/ravendb-go-client"
+	"net/http"
+)
+
+// OperationToggleDatabasesState enables or disables Databases. Disabled databases keep their data but are unloaded
+// and reject any request until they are enabled again.
+type OperationToggleDatabasesState struct {
+	Databases []string
+	Disable   bool
+}
+
+func (operation *OperationToggleDatabasesState) GetCommand(conventions *ravendb.DocumentConventions) (ravendb.RavenCommand, error) {
+	return &toggleDatabasesState{
+		RavenCommandBase: ravendb.RavenCommandBase{
+			ResponseType: ravendb.RavenCommandResponseTypeEmpty,
+		},
+		parent: operation,
+	}, nil
+}
+
+type toggleDatabasesState struct {
+	ravendb.RavenCommandBase
+	parent *OperationToggleDatabasesState
+}
+
+func (c *toggleDatabasesState) CreateRequest(node *ravendb.ServerNode) (*http.Request, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"DatabaseNames": c.parent.Databases,
+	})
+	if err != nil {
+		return nil, err
+	}
+	state := "enable"
+	if c.parent.Disable {
+		state = "disable"
+	}
+	return http.NewRequest(http.MethodPost, node.URL+"/admin/databases/"+state, bytes.NewReader(body))
+}
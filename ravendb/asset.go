@@ -0,0 +1,97 @@
+package ravendb
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"regexp"
+)
+
+const DEFAULT_ASSET_MODE string = "0660"
+
+// Asset is a file uploaded to every node, with the mode and ownership it gets on the host.
+type Asset struct {
+	Content []byte
+	Mode    string
+	Owner   string
+	Group   string
+}
+
+func assetSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		Description: "Files uploaded to every node, their directories are created when missing.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"path": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.StringMatch(regexp.MustCompile(`^/`), "must be an absolute path"),
+				},
+				"content": {
+					Type:         schema.TypeString,
+					Required:     true,
+					Sensitive:    true,
+					Description:  "The content of the file, base64 encoded.",
+					ValidateFunc: validation.StringIsBase64,
+				},
+				"mode": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      DEFAULT_ASSET_MODE,
+					Description:  "The octal permissions of the file, e.g. 0750 for a script.",
+					ValidateFunc: validation.StringMatch(regexp.MustCompile(`^0[0-7]{3}$`), "must be a 4 digits octal mode, e.g. 0640"),
+				},
+				"owner": {
+					Type:     schema.TypeString,
+					Optional: true,
+					Default:  "ravendb",
+				},
+				"group": {
+					Type:     schema.TypeString,
+					Optional: true,
+					Default:  "ravendb",
+				},
+			},
+		},
+	}
+}
+
+// parseAssets merges the asset blocks with the deprecated assets map, whose files keep the 0660 ravendb:ravendb
+// permissions they always had.
+func parseAssets(d *schema.ResourceData) (map[string]Asset, error) {
+	assets := map[string]Asset{}
+	for path, base64Val := range d.Get("assets").(map[string]interface{}) {
+		value, err := base64.StdEncoding.DecodeString(base64Val.(string))
+		if err != nil {
+			return nil, err
+		}
+		assets[path] = Asset{
+			Content: value,
+			Mode:    DEFAULT_ASSET_MODE,
+			Owner:   "ravendb",
+			Group:   "ravendb",
+		}
+	}
+
+	for _, v := range d.Get("asset").([]interface{}) {
+		value := v.(map[string]interface{})
+		path := value["path"].(string)
+		if _, ok := assets[path]; ok {
+			return nil, fmt.Errorf("the asset %s is set more than once", path)
+		}
+		content, err := base64.StdEncoding.DecodeString(value["content"].(string))
+		if err != nil {
+			return nil, err
+		}
+		assets[path] = Asset{
+			Content: content,
+			Mode:    value["mode"].(string),
+			Owner:   value["owner"].(string),
+			Group:   value["group"].(string),
+		}
+	}
+	return assets, nil
+}
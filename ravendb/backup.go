@@ -0,0 +1,228 @@
+package ravendb
+
+import (
+	"errors"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/ravendb/terraform-provider-ravendb/operations"
+)
+
+const (
+	BACKUP_ENCRYPTION_DATABASE_KEY string = "UseDatabaseKey"
+	BACKUP_ENCRYPTION_PROVIDED_KEY string = "UseProvidedKey"
+)
+
+// backupSchema holds the attributes describing the kind of backup taken, how it is encrypted and where it is
+// written to.
+func backupSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"backup_type": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "Backup",
+			ForceNew:     true,
+			Description:  "Backup or Snapshot.",
+			ValidateFunc: validation.StringInSlice([]string{"Backup", "Snapshot"}, false),
+		},
+		"encryption": {
+			Type:     schema.TypeSet,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"mode": {
+						Type:         schema.TypeString,
+						Required:     true,
+						Description:  "UseDatabaseKey encrypts the backups with the key of the (encrypted) database, UseProvidedKey with key.",
+						ValidateFunc: validation.StringInSlice([]string{BACKUP_ENCRYPTION_DATABASE_KEY, BACKUP_ENCRYPTION_PROVIDED_KEY}, false),
+					},
+					"key": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						Sensitive:    true,
+						Description:  "A base64 encoded 256 bit key, required by UseProvidedKey unless generate_key is set.",
+						ValidateFunc: validation.StringIsBase64,
+					},
+					"generate_key": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Description: "Has the server generate the key of UseProvidedKey once, it is then kept in encryption_key.",
+					},
+				},
+			},
+		},
+		"encryption_key": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Sensitive:   true,
+			Description: "The key the backups are encrypted with by UseProvidedKey, needed to restore them.",
+		},
+		"key_export": backupKeyExportSchema(),
+		"local_folder": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The folder on the server the backups are written to.",
+		},
+		"s3": {
+			Type:     schema.TypeSet,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"bucket": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"region": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"remote_folder": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"custom_server_url": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						Description:  "The endpoint of an S3 compatible storage, e.g. MinIO.",
+						ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+					},
+					"force_path_style": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Description: "Addresses the bucket in the path rather than the host name, as most S3 compatible storages expect.",
+					},
+					"access_key": {
+						Type:      schema.TypeString,
+						Optional:  true,
+						Sensitive: true,
+					},
+					"secret_key": {
+						Type:      schema.TypeString,
+						Optional:  true,
+						Sensitive: true,
+					},
+					"session_token": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Sensitive:   true,
+						Description: "The STS session token of temporary access_key and secret_key credentials.",
+					},
+				},
+			},
+		},
+	}
+}
+
+// withBackupSchema adds the backup attributes to a resource schema.
+func withBackupSchema(s map[string]*schema.Schema) map[string]*schema.Schema {
+	for key, value := range backupSchema() {
+		s[key] = value
+	}
+	return s
+}
+
+func parseBackup(d *schema.ResourceData) (operations.BackupConfiguration, error) {
+	configuration := operations.BackupConfiguration{
+		BackupType:               d.Get("backup_type").(string),
+		BackupEncryptionSettings: &operations.BackupEncryptionSettings{EncryptionMode: "None"},
+	}
+
+	if list := d.Get("encryption").(*schema.Set).List(); len(list) > 0 {
+		encryption := list[0].(map[string]interface{})
+		key := encryption["key"].(string)
+		if encryption["generate_key"].(bool) {
+			if key != "" {
+				return configuration, errors.New("encryption key and generate_key are mutually exclusive")
+			}
+			// empty until the key is generated on the first apply
+			key = d.Get("encryption_key").(string)
+		} else if encryption["mode"].(string) == BACKUP_ENCRYPTION_PROVIDED_KEY && key == "" {
+			return configuration, errors.New("encryption key is required by " + BACKUP_ENCRYPTION_PROVIDED_KEY + " unless generate_key is set")
+		}
+		configuration.BackupEncryptionSettings = &operations.BackupEncryptionSettings{
+			Key:            key,
+			EncryptionMode: encryption["mode"].(string),
+		}
+	}
+
+	if folder := d.Get("local_folder").(string); folder != "" {
+		configuration.LocalSettings = &operations.LocalSettings{FolderPath: folder}
+	}
+
+	list := d.Get("s3").(*schema.Set).List()
+	if len(list) == 0 {
+		if configuration.LocalSettings == nil {
+			return configuration, errors.New("either local_folder or s3 is required")
+		}
+		return configuration, nil
+	}
+
+	s3 := list[0].(map[string]interface{})
+	// RavenDB keeps the keys in the task, credentials resolved here would expire under the scheduled backups
+	if s3["access_key"].(string) == "" || s3["secret_key"].(string) == "" {
+		return configuration, errors.New("s3 requires access_key and secret_key")
+	}
+	configuration.S3Settings = &operations.S3Settings{
+		AwsAccessKey:     s3["access_key"].(string),
+		AwsSecretKey:     s3["secret_key"].(string),
+		AwsSessionToken:  s3["session_token"].(string),
+		AwsRegionName:    s3["region"].(string),
+		BucketName:       s3["bucket"].(string),
+		RemoteFolderName: s3["remote_folder"].(string),
+		CustomServerUrl:  s3["custom_server_url"].(string),
+		ForcePathStyle:   s3["force_path_style"].(bool),
+	}
+	return configuration, nil
+}
+
+// flattenBackup returns the backup attributes of a configuration read from the server. Secrets are kept from
+// the resource data, as the server doesn't return the provided encryption key and S3 credentials.
+func flattenBackup(d *schema.ResourceData, configuration operations.BackupConfiguration) map[string]interface{} {
+	values := map[string]interface{}{
+		"backup_type":  configuration.BackupType,
+		"encryption":   []interface{}{},
+		"local_folder": "",
+		"s3":           []interface{}{},
+	}
+
+	if encryption := configuration.BackupEncryptionSettings; encryption != nil && encryption.EncryptionMode != "None" {
+		key := ""
+		generateKey := false
+		if list := d.Get("encryption").(*schema.Set).List(); len(list) > 0 {
+			key = list[0].(map[string]interface{})["key"].(string)
+			generateKey = list[0].(map[string]interface{})["generate_key"].(bool)
+		}
+		values["encryption"] = []interface{}{map[string]interface{}{
+			"mode":         encryption.EncryptionMode,
+			"key":          key,
+			"generate_key": generateKey,
+		}}
+	}
+
+	if local := configuration.LocalSettings; local != nil && !local.Disabled {
+		values["local_folder"] = local.FolderPath
+	}
+
+	s3 := configuration.S3Settings
+	if s3 == nil || s3.Disabled || s3.BucketName == "" {
+		return values
+	}
+	destination := map[string]interface{}{
+		"access_key":    "",
+		"secret_key":    "",
+		"session_token": "",
+	}
+	if list := d.Get("s3").(*schema.Set).List(); len(list) > 0 {
+		for key := range destination {
+			destination[key] = list[0].(map[string]interface{})[key]
+		}
+	}
+	destination["bucket"] = s3.BucketName
+	destination["region"] = s3.AwsRegionName
+	destination["remote_folder"] = s3.RemoteFolderName
+	destination["custom_server_url"] = s3.CustomServerUrl
+	destination["force_path_style"] = s3.ForcePathStyle
+	values["s3"] = []interface{}{destination}
+	return values
+}
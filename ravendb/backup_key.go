@@ -0,0 +1,111 @@
+package ravendb
+
+import (
+	"errors"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/ravendb/ravendb-go-client"
+	"github.com/ravendb/terraform-provider-ravendb/operations"
+)
+
+// BackupKeyExport is the secret store the key of UseProvidedKey is copied to, so it outlives the Terraform state.
+type BackupKeyExport struct {
+	SecretId string
+	Region   string
+}
+
+func backupKeyExportSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Stores the encryption key of UseProvidedKey in a secret store whenever it changes.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"aws_secrets_manager": {
+					Type:     schema.TypeSet,
+					Required: true,
+					MaxItems: 1,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"secret_id": {
+								Type:        schema.TypeString,
+								Required:    true,
+								Description: "The name or ARN of the secret, it is created when missing.",
+							},
+							"region": {
+								Type:     schema.TypeString,
+								Required: true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func parseBackupKeyExport(d *schema.ResourceData) *BackupKeyExport {
+	for _, v := range d.Get("key_export").(*schema.Set).List() {
+		for _, destination := range v.(map[string]interface{})["aws_secrets_manager"].(*schema.Set).List() {
+			value := destination.(map[string]interface{})
+			return &BackupKeyExport{
+				SecretId: value["secret_id"].(string),
+				Region:   value["region"].(string),
+			}
+		}
+	}
+	return nil
+}
+
+// resolveBackupKey generates the key of a UseProvidedKey configuration that has none yet, keeps it in
+// encryption_key and exports it when it changed since the last apply.
+func resolveBackupKey(d *schema.ResourceData, store *ravendb.DocumentStore, configuration *operations.BackupConfiguration) error {
+	encryption := configuration.BackupEncryptionSettings
+	if encryption == nil || encryption.EncryptionMode != BACKUP_ENCRYPTION_PROVIDED_KEY {
+		return d.Set("encryption_key", "")
+	}
+	if encryption.Key == "" {
+		secret := operations.OperationGenerateSecret{}
+		err := executeWithRetries(store, &secret)
+		if err != nil {
+			return err
+		}
+		encryption.Key = secret.Result
+	}
+
+	if export := parseBackupKeyExport(d); export != nil && (encryption.Key != d.Get("encryption_key").(string) || d.HasChange("key_export") || d.IsNewResource()) {
+		err := export.store(encryption.Key)
+		if err != nil {
+			return errors.New("unable to export the backup encryption key to " + export.SecretId + ": " + err.Error())
+		}
+	}
+	return d.Set("encryption_key", encryption.Key)
+}
+
+// store writes key as the current value of the secret, creating the secret when it doesn't exist.
+func (e *BackupKeyExport) store(key string) error {
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            aws.Config{Region: aws.String(e.Region)},
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return err
+	}
+	client := secretsmanager.New(sess)
+	_, err = client.PutSecretValue(&secretsmanager.PutSecretValueInput{
+		SecretId:     aws.String(e.SecretId),
+		SecretString: aws.String(key),
+	})
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == secretsmanager.ErrCodeResourceNotFoundException {
+		_, err = client.CreateSecret(&secretsmanager.CreateSecretInput{
+			Name:         aws.String(e.SecretId),
+			SecretString: aws.String(key),
+		})
+	}
+	return err
+}
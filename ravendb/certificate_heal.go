@@ -0,0 +1,50 @@
+package ravendb
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"strings"
+)
+
+// nodeCertificatePath is where the server certificate of a secured node is uploaded to.
+const nodeCertificatePath = "/etc/ravendb/certificate.pfx"
+
+// certificateOutdated reports whether the server certificate on the host of conn is missing or differs from
+// certificate.
+func certificateOutdated(conn Transport, certificate []byte) bool {
+	output, err := runCommand(conn, "sudo sha256sum "+nodeCertificatePath)
+	if err != nil {
+		return true
+	}
+	sum := sha256.Sum256(certificate)
+	return strings.HasPrefix(string(output), hex.EncodeToString(sum[:])) == false
+}
+
+// certificateDrifted reports whether the certificate read from the host of the node at index is missing or differs
+// from the configured one, e.g. after its disk was restored from a snapshot. Read only reports it, the next apply
+// restores the certificate through configureServer.
+func (sc *ServerConfig) certificateDrifted(index int, current []byte) bool {
+	certificate := sc.nodeCertificate(index)
+	return !sc.Unsecured && certificate != nil && sc.DeployMode != DEPLOY_MODE_CLUSTER_ONLY && string(current) != string(certificate)
+}
+
+func outdatedCertificatesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "The hosts whose certificate was found missing or different from the configured one, restored on the next apply.",
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+}
+
+// customizeOutdatedCertificates plans the restore of the certificates Read found outdated, so the apply
+// reconfigures their nodes.
+func customizeOutdatedCertificates(d *schema.ResourceDiff) error {
+	if d.Id() == "" || len(d.Get("outdated_certificates").([]interface{})) == 0 {
+		return nil
+	}
+	return d.SetNew("outdated_certificates", []string{})
+}
@@ -0,0 +1,100 @@
+package ravendb
+
+import (
+	"crypto/sha1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/ravendb/ravendb-go-client"
+	"github.com/ravendb/terraform-provider-ravendb/operations"
+	"reflect"
+	"strings"
+)
+
+func clientCertificatesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		Description: "Client certificates registered with the cluster once it is formed, e.g. for operators and monitoring systems.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"certificate_pem": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "The PEM encoded public certificate.",
+				},
+				"security_clearance": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "ValidUser",
+					ValidateFunc: validation.StringInSlice([]string{"ClusterAdmin", "Operator", "ValidUser"}, false),
+				},
+				"permissions": {
+					Type:        schema.TypeMap,
+					Optional:    true,
+					Description: "The access level - Admin, ReadWrite or Read - of a ValidUser certificate by database name.",
+					Elem: &schema.Schema{
+						Type:         schema.TypeString,
+						ValidateFunc: validation.StringInSlice([]string{"Admin", "ReadWrite", "Read"}, false),
+					},
+				},
+			},
+		},
+	}
+}
+
+func parseClientCertificates(d *schema.ResourceData) ([]operations.ClientCertificate, error) {
+	var certificates []operations.ClientCertificate
+	for _, v := range d.Get("client_certificates").([]interface{}) {
+		value := v.(map[string]interface{})
+		block, _ := pem.Decode([]byte(value["certificate_pem"].(string)))
+		if block == nil || block.Type != "CERTIFICATE" {
+			return nil, errors.New("client certificate " + value["name"].(string) + " is not a PEM encoded certificate")
+		}
+
+		permissions := map[string]string{}
+		for database, access := range value["permissions"].(map[string]interface{}) {
+			permissions[database] = access.(string)
+		}
+		certificates = append(certificates, operations.ClientCertificate{
+			Name:              value["name"].(string),
+			Certificate:       block.Bytes,
+			Thumbprint:        strings.ToUpper(fmt.Sprintf("%x", sha1.Sum(block.Bytes))),
+			SecurityClearance: value["security_clearance"].(string),
+			Permissions:       permissions,
+		})
+	}
+	return certificates, nil
+}
+
+// registerClientCertificates registers the client certificates that aren't known to the cluster yet, and updates
+// the ones whose name, clearance or permissions changed.
+func (sc *ServerConfig) registerClientCertificates(store *ravendb.DocumentStore) error {
+	if sc.Unsecured && len(sc.ClientCertificates) > 0 {
+		return errors.New("client certificates can only be registered with a secured cluster")
+	}
+	for _, certificate := range sc.ClientCertificates {
+		existing := operations.OperationGetCertificate{Thumbprint: certificate.Thumbprint}
+		err := executeWithRetries(store, &existing)
+		if err != nil {
+			return err
+		}
+
+		if existing.Result == nil {
+			err = executeWithRetries(store, &operations.OperationPutClientCertificate{Certificate: certificate})
+		} else if existing.Result.Name != certificate.Name || existing.Result.SecurityClearance != certificate.SecurityClearance ||
+			len(existing.Result.Permissions)+len(certificate.Permissions) > 0 && !reflect.DeepEqual(existing.Result.Permissions, certificate.Permissions) {
+			err = executeWithRetries(store, &operations.OperationEditClientCertificate{Certificate: certificate})
+		}
+		if err != nil {
+			return fmt.Errorf("registering client certificate %s: %w", certificate.Name, err)
+		}
+	}
+	return nil
+}
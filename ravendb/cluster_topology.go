@@ -0,0 +1,58 @@
+package ravendb
+
+import (
+	"encoding/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	internal_operations "github.com/ravendb/terraform-provider-ravendb/operations"
+	"sort"
+)
+
+type topologyNode struct {
+	Tag  string `json:"tag"`
+	Url  string `json:"url"`
+	Role string `json:"role"`
+}
+
+type clusterTopology struct {
+	TopologyId string         `json:"topology_id"`
+	Leader     string         `json:"leader"`
+	Nodes      []topologyNode `json:"nodes"`
+}
+
+// setClusterTopology sets cluster_topology to the topology the first node reports, with the nodes sorted by tag.
+func (sc *ServerConfig) setClusterTopology(d *schema.ResourceData) error {
+	store, err := getStore(sc, 0)
+	if err != nil {
+		return err
+	}
+	state := internal_operations.OperationGetClusterState{}
+	err = executeWithRetries(store, &state)
+	if err != nil {
+		return err
+	}
+
+	topology := clusterTopology{
+		TopologyId: state.Result.Topology.TopologyId,
+		Leader:     state.Result.Leader,
+		Nodes:      []topologyNode{},
+	}
+	roles := map[string]map[string]string{
+		"Member":     state.Result.Topology.Members,
+		"Promotable": state.Result.Topology.Promotables,
+		"Watcher":    state.Result.Topology.Watchers,
+	}
+	for role, nodes := range roles {
+		for tag, url := range nodes {
+			topology.Nodes = append(topology.Nodes, topologyNode{Tag: tag, Url: url, Role: role})
+		}
+	}
+	sort.Slice(topology.Nodes, func(i, j int) bool {
+		return topology.Nodes[i].Tag < topology.Nodes[j].Tag
+	})
+
+	value, err := json.Marshal(topology)
+	if err != nil {
+		return err
+	}
+	return d.Set("cluster_topology", string(value))
+}
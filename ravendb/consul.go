@@ -0,0 +1,271 @@
+package ravendb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type Consul struct {
+	Address       string
+	Token         string
+	ServiceName   string
+	CheckInterval string
+	TLSSkipVerify bool
+}
+
+type consulCheck struct {
+	HTTP          string `json:",omitempty"`
+	TCP           string `json:",omitempty"`
+	Interval      string
+	TLSSkipVerify bool
+}
+
+type consulService struct {
+	ID      string
+	Name    string
+	Address string
+	Port    int
+	Tags    []string
+	Meta    map[string]string
+	Check   consulCheck
+}
+
+func consulSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Registers the HTTP and TCP endpoints of every node as Consul services once the cluster is deployed.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"address": {
+					Type:         schema.TypeString,
+					Required:     true,
+					Description:  "The url of the Consul agent the services are registered with.",
+					ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				},
+				"token": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					DefaultFunc: schema.EnvDefaultFunc("CONSUL_HTTP_TOKEN", ""),
+				},
+				"service_name": {
+					Type:     schema.TypeString,
+					Optional: true,
+					Default:  "ravendb",
+				},
+				"check_interval": {
+					Type:     schema.TypeString,
+					Optional: true,
+					Default:  "10s",
+				},
+				"tls_skip_verify": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Description: "Doesn't verify the certificate of the nodes in the HTTP health checks, e.g. when the agents don't trust the authority that issued it.",
+				},
+			},
+		},
+	}
+}
+
+func parseConsul(d *schema.ResourceData) *Consul {
+	return consulFromList(d.Get("consul").(*schema.Set).List())
+}
+
+func consulFromList(list []interface{}) *Consul {
+	for _, v := range list {
+		value := v.(map[string]interface{})
+		return &Consul{
+			Address:       strings.TrimSuffix(value["address"].(string), "/"),
+			Token:         value["token"].(string),
+			ServiceName:   value["service_name"].(string),
+			CheckInterval: value["check_interval"].(string),
+			TLSSkipVerify: value["tls_skip_verify"].(bool),
+		}
+	}
+	return nil
+}
+
+func (c *Consul) services(sc *ServerConfig) ([]consulService, error) {
+	scheme := "https"
+	if sc.Unsecured {
+		scheme = "http"
+	}
+	var services []consulService
+	for index, host := range sc.Hosts {
+		httpUrl, tcpUrl, err := sc.GetUrlByIndex(index, scheme)
+		if err != nil {
+			return nil, err
+		}
+		publicUrl, err := url.Parse(httpUrl)
+		if err != nil {
+			return nil, err
+		}
+		tcp, err := url.Parse(tcpUrl)
+		if err != nil {
+			return nil, err
+		}
+		httpPort := DEFAULT_SECURE_RAVENDB_HTTP_PORT
+		if sc.Unsecured {
+			httpPort = DEFAULT_HTTP_PORT
+		}
+		if publicUrl.Port() != "" {
+			httpPort, err = strconv.Atoi(publicUrl.Port())
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		meta := map[string]string{"url": httpUrl, "host": host}
+		ids := c.serviceIDs(host)
+		services = append(services, consulService{
+			ID:      ids[0],
+			Name:    c.ServiceName,
+			Address: publicUrl.Hostname(),
+			Port:    httpPort,
+			Tags:    []string{"http"},
+			Meta:    meta,
+			Check: consulCheck{
+				HTTP:          httpUrl + "/setup/alive",
+				Interval:      c.CheckInterval,
+				TLSSkipVerify: c.TLSSkipVerify,
+			},
+		}, consulService{
+			ID:      ids[1],
+			Name:    c.ServiceName + "-tcp",
+			Address: tcp.Hostname(),
+			Port:    sc.Url.TcpPort,
+			Tags:    []string{"tcp"},
+			Meta:    meta,
+			Check: consulCheck{
+				TCP:      net.JoinHostPort(tcp.Hostname(), strconv.Itoa(sc.Url.TcpPort)),
+				Interval: c.CheckInterval,
+			},
+		})
+	}
+	return services, nil
+}
+
+// serviceIDs returns the ids of the HTTP and TCP services of host.
+func (c *Consul) serviceIDs(host string) []string {
+	return []string{c.ServiceName + "-" + host, c.ServiceName + "-tcp-" + host}
+}
+
+func (c *Consul) register(sc *ServerConfig) error {
+	if c == nil {
+		return nil
+	}
+	services, err := c.services(sc)
+	if err != nil {
+		return err
+	}
+	for _, service := range services {
+		body, err := json.Marshal(service)
+		if err != nil {
+			return err
+		}
+		err = c.send("/v1/agent/service/register", body)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Consul) deregister(sc *ServerConfig) error {
+	if c == nil {
+		return nil
+	}
+	var ids []string
+	for _, host := range sc.Hosts {
+		ids = append(ids, c.serviceIDs(host)...)
+	}
+	return c.deregisterIDs(ids)
+}
+
+func (c *Consul) deregisterIDs(ids []string) error {
+	var result error
+	for _, id := range ids {
+		err := c.send("/v1/agent/service/deregister/"+url.PathEscape(id), nil)
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	return result
+}
+
+// deregisterRemovedServices deregisters the services registered by the previous apply that this one no longer
+// registers: those of the removed hosts, those under a previous service_name, and all of them when the consul
+// block was removed or points to another agent.
+func (sc *ServerConfig) deregisterRemovedServices(d *schema.ResourceData) error {
+	if !d.HasChanges("consul", "hosts", "node") {
+		return nil
+	}
+	before, _ := d.GetChange("consul")
+	previous := consulFromList(before.(*schema.Set).List())
+	if previous == nil {
+		return nil
+	}
+	previousHosts, _, err := nodeLists(func(key string) interface{} {
+		old, _ := d.GetChange(key)
+		return old
+	})
+	if err != nil {
+		return err
+	}
+	return previous.deregisterIDs(removedServiceIDs(previous, previousHosts, sc.Consul, sc.Hosts))
+}
+
+// removedServiceIDs returns the ids of the services of previous on previousHosts that current doesn't register
+// on the same agent for hosts.
+func removedServiceIDs(previous *Consul, previousHosts []string, current *Consul, hosts []string) []string {
+	registered := map[string]bool{}
+	if current != nil && current.Address == previous.Address {
+		for _, host := range hosts {
+			for _, id := range current.serviceIDs(host) {
+				registered[id] = true
+			}
+		}
+	}
+	var removed []string
+	for _, host := range previousHosts {
+		for _, id := range previous.serviceIDs(host) {
+			if !registered[id] {
+				removed = append(removed, id)
+			}
+		}
+	}
+	return removed
+}
+
+func (c *Consul) send(path string, body []byte) error {
+	request, err := http.NewRequest(http.MethodPut, c.Address+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if c.Token != "" {
+		request.Header.Set("X-Consul-Token", c.Token)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		output, _ := ioutil.ReadAll(response.Body)
+		return errors.New("consul request " + path + " failed with HTTP status code: " + response.Status + "\n" + string(output))
+	}
+	return nil
+}
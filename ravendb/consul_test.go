@@ -0,0 +1,31 @@
+package ravendb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRemovedServiceIDs(t *testing.T) {
+	previous := &Consul{Address: "http://consul:8500", ServiceName: "ravendb"}
+	hosts := []string{"10.0.0.1", "10.0.0.2"}
+
+	removed := removedServiceIDs(previous, hosts, previous, hosts[:1])
+	if expected := []string{"ravendb-10.0.0.2", "ravendb-tcp-10.0.0.2"}; !reflect.DeepEqual(removed, expected) {
+		t.Errorf("expected the services of the removed host %v, got %v", expected, removed)
+	}
+
+	removed = removedServiceIDs(previous, hosts, nil, hosts)
+	if len(removed) != 4 {
+		t.Errorf("expected every service to be removed with the consul block, got %v", removed)
+	}
+
+	renamed := &Consul{Address: previous.Address, ServiceName: "raven"}
+	removed = removedServiceIDs(previous, hosts, renamed, hosts)
+	if len(removed) != 4 || removed[0] != "ravendb-10.0.0.1" {
+		t.Errorf("expected the services of the previous service_name to be removed, got %v", removed)
+	}
+
+	if removed = removedServiceIDs(previous, hosts, previous, hosts); len(removed) != 0 {
+		t.Errorf("expected no service to be removed, got %v", removed)
+	}
+}
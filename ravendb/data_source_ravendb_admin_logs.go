@@ -0,0 +1,121 @@
+package ravendb
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/ravendb/terraform-provider-ravendb/operations"
+	"sort"
+	"strings"
+	"time"
+)
+
+const errorAdminLogsRead = "error reading RavenDB server logs: %s"
+
+func dataSourceRavendbAdminLogs() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAdminLogsRead,
+
+		Schema: withConnectionSchema(map[string]*schema.Schema{
+			"from": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Only return entries logged after this RFC3339 timestamp.",
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"lines": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      100,
+				Description:  "The maximum number of (most recent) entries to return.",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"filter": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return entries containing this text.",
+			},
+			"entries": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		}),
+	}
+}
+
+func dataSourceAdminLogsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	store, err := getStoreFromData(d, meta, "")
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorAdminLogsRead, err.Error()))
+	}
+
+	operation := operations.OperationDownloadLogs{}
+	if from, ok := d.GetOk("from"); ok {
+		operation.From, err = time.Parse(time.RFC3339, from.(string))
+		if err != nil {
+			return diag.FromErr(fmt.Errorf(errorAdminLogsRead, err.Error()))
+		}
+	}
+	err = executeWithRetries(store, &operation)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorAdminLogsRead, err.Error()))
+	}
+
+	entries, err := readLogEntries(operation.Result, d.Get("filter").(string), d.Get("lines").(int))
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorAdminLogsRead, err.Error()))
+	}
+
+	err = d.Set("entries", entries)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorAdminLogsRead, err.Error()))
+	}
+	d.SetId(time.Now().UTC().Format(time.RFC3339))
+
+	return nil
+}
+
+// readLogEntries extracts the last lines of the log files in the downloaded zip, oldest file first.
+func readLogEntries(archive []byte, filter string, lines int) ([]string, error) {
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, err
+	}
+	files := reader.File
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+
+	var entries []string
+	for _, file := range files {
+		content, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(content)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line == "" || filter != "" && strings.Contains(line, filter) == false {
+				continue
+			}
+			entries = append(entries, line)
+			if len(entries) > lines {
+				entries = entries[1:]
+			}
+		}
+		content.Close()
+		if err = scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
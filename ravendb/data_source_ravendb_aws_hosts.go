@@ -0,0 +1,155 @@
+package ravendb
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"sort"
+	"strings"
+)
+
+const errorAwsHostsRead = "error discovering RavenDB hosts on AWS: %s"
+
+func dataSourceRavendbAwsHosts() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAwsHostsRead,
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The AWS region to look for instances in. The default region of the AWS credentials chain is used when omitted.",
+			},
+			"tags": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Only instances having all of these tags are returned.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"autoscaling_group_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only instances that belong to this autoscaling group are returned.",
+			},
+			"use_private_ip": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"instance_ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"hosts": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The ip addresses of the running instances, ordered by instance id.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAwsHostsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := aws.Config{}
+	if region, ok := d.GetOk("region"); ok {
+		config.Region = aws.String(region.(string))
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            config,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorAwsHostsRead, err.Error()))
+	}
+
+	input := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: []*string{aws.String("running")},
+			},
+		},
+	}
+	for key, value := range d.Get("tags").(map[string]interface{}) {
+		input.Filters = append(input.Filters, &ec2.Filter{
+			Name:   aws.String("tag:" + key),
+			Values: []*string{aws.String(value.(string))},
+		})
+	}
+
+	if groupName, ok := d.GetOk("autoscaling_group_name"); ok {
+		groups, err := autoscaling.New(sess).DescribeAutoScalingGroupsWithContext(ctx, &autoscaling.DescribeAutoScalingGroupsInput{
+			AutoScalingGroupNames: []*string{aws.String(groupName.(string))},
+		})
+		if err != nil {
+			return diag.FromErr(fmt.Errorf(errorAwsHostsRead, err.Error()))
+		}
+		if len(groups.AutoScalingGroups) == 0 {
+			return diag.FromErr(fmt.Errorf(errorAwsHostsRead, "autoscaling group "+groupName.(string)+" was not found"))
+		}
+		for _, instance := range groups.AutoScalingGroups[0].Instances {
+			input.InstanceIds = append(input.InstanceIds, instance.InstanceId)
+		}
+		if len(input.InstanceIds) == 0 {
+			return setDiscoveredHosts(d, "aws/"+groupName.(string), nil, nil)
+		}
+	}
+
+	hosts := map[string]string{}
+	usePrivateIp := d.Get("use_private_ip").(bool)
+	err = ec2.New(sess).DescribeInstancesPagesWithContext(ctx, input, func(output *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, reservation := range output.Reservations {
+			for _, instance := range reservation.Instances {
+				ip := instance.PublicIpAddress
+				if usePrivateIp {
+					ip = instance.PrivateIpAddress
+				}
+				if ip != nil {
+					hosts[aws.StringValue(instance.InstanceId)] = aws.StringValue(ip)
+				}
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorAwsHostsRead, err.Error()))
+	}
+
+	ids := make([]string, 0, len(hosts))
+	for id := range hosts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	ips := make([]string, len(ids))
+	for i, id := range ids {
+		ips[i] = hosts[id]
+	}
+
+	return setDiscoveredHosts(d, "aws/"+strings.Join(ids, ","), ids, ips)
+}
+
+// setDiscoveredHosts stores the result of a host discovery data source.
+func setDiscoveredHosts(d *schema.ResourceData, id string, instanceIds []string, hosts []string) diag.Diagnostics {
+	err := d.Set("instance_ids", instanceIds)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("hosts", hosts)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(id)
+	return nil
+}
@@ -0,0 +1,295 @@
+package ravendb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	errorAzureHostsRead = "error discovering RavenDB hosts on Azure: %s"
+	azureManagementUrl  = "https://management.azure.com"
+)
+
+type azureIpConfiguration struct {
+	Properties struct {
+		Primary          bool   `json:"primary"`
+		PrivateIPAddress string `json:"privateIPAddress"`
+		PublicIPAddress  *struct {
+			Id string `json:"id"`
+		} `json:"publicIPAddress"`
+	} `json:"properties"`
+}
+
+type azureNetworkInterface struct {
+	Id         string `json:"id"`
+	Properties struct {
+		VirtualMachine *struct {
+			Id string `json:"id"`
+		} `json:"virtualMachine"`
+		IpConfigurations []azureIpConfiguration `json:"ipConfigurations"`
+	} `json:"properties"`
+}
+
+type azureResource struct {
+	Id         string            `json:"id"`
+	Tags       map[string]string `json:"tags"`
+	Properties struct {
+		IpAddress string `json:"ipAddress"`
+	} `json:"properties"`
+}
+
+func dataSourceRavendbAzureHosts() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAzureHostsRead,
+
+		Schema: map[string]*schema.Schema{
+			"subscription_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ARM_SUBSCRIPTION_ID", nil),
+			},
+			"tenant_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ARM_TENANT_ID", nil),
+			},
+			"client_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ARM_CLIENT_ID", nil),
+			},
+			"client_secret": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("ARM_CLIENT_SECRET", nil),
+			},
+			"access_token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "An Azure Resource Manager access token to use instead of the client credentials.",
+			},
+			"resource_group": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"scale_set_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only instances of this virtual machine scale set are returned.",
+			},
+			"tags": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Only virtual machines having all of these tags are returned. Ignored for scale sets.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"use_private_ip": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"instance_ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"hosts": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The ip addresses of the virtual machines, ordered by resource id.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAzureHostsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	subscription := d.Get("subscription_id").(string)
+	if subscription == "" {
+		return diag.FromErr(fmt.Errorf(errorAzureHostsRead, "subscription_id (or ARM_SUBSCRIPTION_ID) must be set"))
+	}
+
+	token := d.Get("access_token").(string)
+	if token == "" {
+		var err error
+		token, err = azureAccessToken(ctx, d.Get("tenant_id").(string), d.Get("client_id").(string), d.Get("client_secret").(string))
+		if err != nil {
+			return diag.FromErr(fmt.Errorf(errorAzureHostsRead, err.Error()))
+		}
+	}
+
+	group := azureManagementUrl + "/subscriptions/" + subscription + "/resourceGroups/" + d.Get("resource_group").(string)
+	nicsUrl := group + "/providers/Microsoft.Network/networkInterfaces?api-version=2021-02-01"
+	publicIpsUrl := group + "/providers/Microsoft.Network/publicIPAddresses?api-version=2021-02-01"
+	var vms map[string]bool
+
+	if scaleSet, ok := d.GetOk("scale_set_name"); ok {
+		scaleSetUrl := group + "/providers/Microsoft.Compute/virtualMachineScaleSets/" + scaleSet.(string)
+		nicsUrl = scaleSetUrl + "/networkInterfaces?api-version=2018-10-01"
+		publicIpsUrl = scaleSetUrl + "/publicipaddresses?api-version=2018-10-01"
+	} else {
+		var machines []azureResource
+		err := azureList(ctx, token, group+"/providers/Microsoft.Compute/virtualMachines?api-version=2021-07-01", &machines)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf(errorAzureHostsRead, err.Error()))
+		}
+		vms = map[string]bool{}
+		tags := d.Get("tags").(map[string]interface{})
+		for _, machine := range machines {
+			if hasTags(machine.Tags, tags) {
+				vms[strings.ToLower(machine.Id)] = true
+			}
+		}
+	}
+
+	var nics []azureNetworkInterface
+	err := azureList(ctx, token, nicsUrl, &nics)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorAzureHostsRead, err.Error()))
+	}
+
+	publicIps := map[string]string{}
+	usePrivateIp := d.Get("use_private_ip").(bool)
+	if usePrivateIp == false {
+		var addresses []azureResource
+		err = azureList(ctx, token, publicIpsUrl, &addresses)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf(errorAzureHostsRead, err.Error()))
+		}
+		for _, address := range addresses {
+			publicIps[strings.ToLower(address.Id)] = address.Properties.IpAddress
+		}
+	}
+
+	hosts := map[string]string{}
+	for _, nic := range nics {
+		if nic.Properties.VirtualMachine == nil {
+			continue
+		}
+		vm := strings.ToLower(nic.Properties.VirtualMachine.Id)
+		if vms != nil && vms[vm] == false {
+			continue
+		}
+		for _, ipConfiguration := range nic.Properties.IpConfigurations {
+			if ipConfiguration.Properties.Primary == false {
+				continue
+			}
+			if usePrivateIp {
+				hosts[vm] = ipConfiguration.Properties.PrivateIPAddress
+			} else if ipConfiguration.Properties.PublicIPAddress != nil {
+				hosts[vm] = publicIps[strings.ToLower(ipConfiguration.Properties.PublicIPAddress.Id)]
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(hosts))
+	for id, ip := range hosts {
+		if ip != "" {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	ips := make([]string, len(ids))
+	for i, id := range ids {
+		ips[i] = hosts[id]
+	}
+
+	return setDiscoveredHosts(d, "azure/"+group+"/"+strings.Join(ids, ","), ids, ips)
+}
+
+func hasTags(actual map[string]string, expected map[string]interface{}) bool {
+	for key, value := range expected {
+		if actual[key] != value.(string) {
+			return false
+		}
+	}
+	return true
+}
+
+func azureAccessToken(ctx context.Context, tenant string, clientId string, clientSecret string) (string, error) {
+	if tenant == "" || clientId == "" || clientSecret == "" {
+		return "", errors.New("either access_token or tenant_id, client_id and client_secret must be set")
+	}
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {clientId},
+		"client_secret": {clientSecret},
+		"scope":         {azureManagementUrl + "/.default"},
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://login.microsoftonline.com/"+tenant+"/oauth2/v2.0/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	err = doJsonRequest(request, &token)
+	if err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+// azureList reads all the pages of an Azure Resource Manager list operation into out.
+func azureList(ctx context.Context, token string, link string, out interface{}) error {
+	var values []json.RawMessage
+	for link != "" {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+		if err != nil {
+			return err
+		}
+		request.Header.Set("Authorization", "Bearer "+token)
+
+		var page struct {
+			Value    []json.RawMessage `json:"value"`
+			NextLink string            `json:"nextLink"`
+		}
+		err = doJsonRequest(request, &page)
+		if err != nil {
+			return err
+		}
+		values = append(values, page.Value...)
+		link = page.NextLink
+	}
+
+	all, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(all, out)
+}
+
+func doJsonRequest(request *http.Request, out interface{}) error {
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return errors.New(request.Method + " " + request.URL.Host + request.URL.Path + " failed with HTTP status code: " + response.Status + "\n" + string(body))
+	}
+	return json.Unmarshal(body, out)
+}
@@ -0,0 +1,244 @@
+package ravendb
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"path"
+	"sort"
+	"strings"
+)
+
+const errorCloudInitRead = "error rendering the RavenDB cloud-init user-data: %s"
+
+// dataSourceRavendbCloudInit renders the installation and configuration of a node as cloud-init user-data, so it is
+// set up at instance launch and only the cluster is left to a ravendb_server with deploy_mode cluster_only.
+func dataSourceRavendbCloudInit() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceCloudInitRead,
+
+		Schema: map[string]*schema.Schema{
+			"url": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The public url of the node.",
+			},
+			"http_port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IsPortNumber,
+			},
+			"tcp_port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IsPortNumber,
+			},
+			"os_family": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      OS_FAMILY_DEBIAN,
+				Description:  "The family of the distribution of the image, debian or rhel.",
+				ValidateFunc: validation.StringInSlice([]string{OS_FAMILY_DEBIAN, OS_FAMILY_RHEL}, false),
+			},
+			"package": {
+				Type:     schema.TypeList,
+				Required: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"version": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The exact RavenDB version to install, constraints aren't resolved here.",
+						},
+						"arch": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"channel": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice([]string{PACKAGE_CHANNEL_DAILY}, false),
+						},
+					},
+				},
+			},
+			"license": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Sensitive:    true,
+				ValidateFunc: validation.StringIsBase64,
+			},
+			"certificate": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				Description:  "The server certificate of the node, base64 encoded. Required unless unsecured is set.",
+				ValidateFunc: validation.StringIsBase64,
+			},
+			"unsecured": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"offline_license": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"settings_override": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"asset": assetSchema(),
+			"settings_json": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The settings.json written on the node.",
+			},
+			"user_data": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The cloud-config user-data, it holds the license and the certificate of the node.",
+			},
+		},
+	}
+}
+
+func dataSourceCloudInitRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	sc, err := parseCloudInitData(d)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorCloudInitRead, err.Error()))
+	}
+
+	settingsJson, userData, err := sc.cloudInit(d.Get("os_family").(string))
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorCloudInitRead, err.Error()))
+	}
+
+	d.SetId(d.Get("url").(string))
+	err = d.Set("settings_json", string(settingsJson))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("user_data", userData)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func parseCloudInitData(d *schema.ResourceData) (ServerConfig, error) {
+	var sc ServerConfig
+	var err error
+	sc.Unsecured = d.Get("unsecured").(bool)
+	sc.OfflineLicense = d.Get("offline_license").(bool)
+	sc.Url.List = []string{d.Get("url").(string)}
+	sc.Url.HttpPort, sc.Url.TcpPort = parsePorts(map[string]interface{}{
+		"http_port": d.Get("http_port"),
+		"tcp_port":  d.Get("tcp_port"),
+	}, sc.Unsecured)
+
+	value := d.Get("package").([]interface{})[0].(map[string]interface{})
+	sc.Package.Version = value["version"].(string)
+	sc.Package.Arch = packageArch(value["arch"].(string))
+	sc.Package.Channel = value["channel"].(string)
+
+	sc.License, err = base64.StdEncoding.DecodeString(d.Get("license").(string))
+	if err != nil {
+		return sc, err
+	}
+	sc.ClusterCertificate, err = base64.StdEncoding.DecodeString(d.Get("certificate").(string))
+	if err != nil {
+		return sc, err
+	}
+	if len(sc.ClusterCertificate) == 0 && sc.Unsecured == false {
+		return sc, fmt.Errorf("certificate is required unless unsecured is set")
+	}
+
+	sc.Settings = map[string]interface{}{}
+	for key, value := range d.Get("settings_override").(map[string]interface{}) {
+		sc.Settings[key] = value.(string)
+	}
+	// the deprecated assets map isn't part of this data source
+	sc.Assets = map[string]Asset{}
+	for _, v := range d.Get("asset").([]interface{}) {
+		value := v.(map[string]interface{})
+		content, err := base64.StdEncoding.DecodeString(value["content"].(string))
+		if err != nil {
+			return sc, err
+		}
+		sc.Assets[value["path"].(string)] = Asset{
+			Content: content,
+			Mode:    value["mode"].(string),
+			Owner:   value["owner"].(string),
+			Group:   value["group"].(string),
+		}
+	}
+	return sc, nil
+}
+
+// cloudInit renders the settings.json of the first node of sc, and the cloud-config installing RavenDB on a
+// distribution of the given family and writing its files. The files are written once the package created the
+// ravendb user, so they go through runcmd rather than write_files.
+func (sc *ServerConfig) cloudInit(family string) ([]byte, string, error) {
+	var settings map[string]interface{}
+	err := json.Unmarshal([]byte(ravendbDefaultSettings), &settings)
+	if err != nil {
+		return nil, "", err
+	}
+	if sc.Unsecured == false {
+		settings["Security.Certificate.Path"] = nodeCertificatePath
+	}
+	_, err = sc.applySettings(0, settings)
+	if err != nil {
+		return nil, "", err
+	}
+	settingsJson, err := json.MarshalIndent(settings, "", "\t")
+	if err != nil {
+		return nil, "", err
+	}
+
+	files := map[string]Asset{}
+	for assetPath, asset := range sc.Assets {
+		files[assetPath] = asset
+	}
+	files["/etc/ravendb/license.json"] = Asset{Content: sc.License, Mode: DEFAULT_ASSET_MODE, Owner: "ravendb", Group: "ravendb"}
+	files["/etc/ravendb/settings.json"] = Asset{Content: settingsJson, Mode: DEFAULT_ASSET_MODE, Owner: "ravendb", Group: "ravendb"}
+	if sc.Unsecured == false {
+		files[nodeCertificatePath] = Asset{Content: sc.ClusterCertificate, Mode: DEFAULT_ASSET_MODE, Owner: "ravendb", Group: "ravendb"}
+	}
+
+	commands := append([]string{"cd /tmp"}, sc.installCommands(family, sc.Package)...)
+	paths := make([]string, 0, len(files))
+	for filePath := range files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+	for _, filePath := range paths {
+		file := files[filePath]
+		commands = append(commands, "mkdir -p '"+path.Dir(filePath)+"' && echo '"+base64.StdEncoding.EncodeToString(file.Content)+"' | base64 -d > '"+filePath+"'"+
+			" && chown "+file.Owner+":"+file.Group+" '"+filePath+"' && chmod "+file.Mode+" '"+filePath+"'")
+	}
+	commands = append(commands, "systemctl enable ravendb && systemctl restart ravendb")
+
+	var userData strings.Builder
+	userData.WriteString("#cloud-config\nruncmd:\n")
+	// a JSON string is a valid YAML double-quoted scalar
+	encoder := json.NewEncoder(&userData)
+	encoder.SetEscapeHTML(false)
+	for _, cmd := range commands {
+		userData.WriteString("  - ")
+		err = encoder.Encode(cmd)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	return settingsJson, userData.String(), nil
+}
@@ -0,0 +1,52 @@
+package ravendb
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCloudInitWritesFilesAfterInstall(t *testing.T) {
+	sc := ServerConfig{
+		License:            []byte(`{"Id": "license"}`),
+		ClusterCertificate: []byte("certificate"),
+		Package:            Package{Version: "5.4.107", Arch: packageArch("")},
+		Settings:           map[string]interface{}{"Indexing.MapBatchSize": "16384"},
+	}
+	sc.Url.List = []string{"https://a.example.com"}
+
+	settingsJson, userData, err := sc.cloudInit(OS_FAMILY_DEBIAN)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var settings map[string]interface{}
+	err = json.Unmarshal(settingsJson, &settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"PublicServerUrl":           "https://a.example.com",
+		"Security.Certificate.Path": nodeCertificatePath,
+		"Indexing.MapBatchSize":     "16384",
+		"DataDir":                   "/var/lib/ravendb/data",
+	}
+	for key, value := range expected {
+		if settings[key] != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, settings[key])
+		}
+	}
+
+	if !strings.HasPrefix(userData, "#cloud-config\nruncmd:\n") {
+		t.Fatalf("unexpected user-data header:\n%s", userData)
+	}
+	install := strings.Index(userData, "apt-get install")
+	certificate := strings.Index(userData, base64.StdEncoding.EncodeToString([]byte("certificate")))
+	if install < 0 || certificate < install {
+		t.Errorf("expected the certificate to be written after the package is installed:\n%s", userData)
+	}
+	if !strings.HasSuffix(userData, "  - \"systemctl enable ravendb && systemctl restart ravendb\"\n") {
+		t.Errorf("expected RavenDB to be restarted last:\n%s", userData)
+	}
+}
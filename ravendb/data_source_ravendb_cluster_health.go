@@ -0,0 +1,123 @@
+package ravendb
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/ravendb/terraform-provider-ravendb/operations"
+	"sort"
+)
+
+const errorClusterHealthRead = "error reading RavenDB cluster health: %s"
+
+func dataSourceRavendbClusterHealth() *schema.Resource {
+	computedList := func(description string) *schema.Schema {
+		return &schema.Schema{
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: description,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		}
+	}
+	computedBool := func(description string) *schema.Schema {
+		return &schema.Schema{
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: description,
+		}
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourceClusterHealthRead,
+
+		Schema: withConnectionSchema(map[string]*schema.Schema{
+			"healthy":             computedBool("All the conditions below hold."),
+			"leader_present":      computedBool("The cluster has a leader."),
+			"all_nodes_members":   computedBool("Every node of the cluster is a member, none is a promotable or a watcher."),
+			"all_nodes_connected": computedBool("The leader is connected to every other node."),
+			"no_rehab_databases":  computedBool("No database has a node in rehab."),
+			"license_valid":       computedBool("The cluster is activated with a license that hasn't expired."),
+			"leader": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"non_member_nodes":   computedList("The tags of the promotable and watcher nodes, sorted."),
+			"disconnected_nodes": computedList("The tags of the nodes the leader isn't connected to, sorted."),
+			"rehab_databases":    computedList("The databases with a node in rehab, sorted."),
+		}),
+	}
+}
+
+func dataSourceClusterHealthRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	store, err := getStoreFromData(d, meta, "")
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorClusterHealthRead, err.Error()))
+	}
+
+	state := operations.OperationGetClusterState{}
+	err = executeWithRetries(store, &state)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorClusterHealthRead, err.Error()))
+	}
+	databases := operations.OperationGetDatabases{}
+	err = executeWithRetries(store, &databases)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorClusterHealthRead, err.Error()))
+	}
+	license := operations.OperationGetLicenseStatus{}
+	err = executeWithRetries(store, &license)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorClusterHealthRead, err.Error()))
+	}
+
+	nonMembers := make([]string, 0)
+	for _, nodes := range []map[string]string{state.Result.Topology.Promotables, state.Result.Topology.Watchers} {
+		for tag := range nodes {
+			nonMembers = append(nonMembers, tag)
+		}
+	}
+	sort.Strings(nonMembers)
+
+	disconnected := make([]string, 0)
+	for tag, status := range state.Result.Status {
+		if status.Connected == false {
+			disconnected = append(disconnected, tag)
+		}
+	}
+	sort.Strings(disconnected)
+
+	rehabs := make([]string, 0)
+	for _, database := range databases.Result {
+		if len(database.NodesTopology.Rehabs) > 0 {
+			rehabs = append(rehabs, database.Name)
+		}
+	}
+	sort.Strings(rehabs)
+
+	leaderPresent := state.Result.Leader != ""
+	licenseValid := license.Result.Expired == false && license.Result.Type != "" && license.Result.Type != "None" && license.Result.Type != "Invalid"
+	values := map[string]interface{}{
+		"healthy":             leaderPresent && len(nonMembers) == 0 && len(disconnected) == 0 && len(rehabs) == 0 && licenseValid,
+		"leader_present":      leaderPresent,
+		"all_nodes_members":   len(nonMembers) == 0,
+		"all_nodes_connected": len(disconnected) == 0,
+		"no_rehab_databases":  len(rehabs) == 0,
+		"license_valid":       licenseValid,
+		"leader":              state.Result.Leader,
+		"non_member_nodes":    nonMembers,
+		"disconnected_nodes":  disconnected,
+		"rehab_databases":     rehabs,
+	}
+	for key, value := range values {
+		err = d.Set(key, value)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf(errorClusterHealthRead, err.Error()))
+		}
+	}
+	d.SetId("cluster-health")
+
+	return nil
+}
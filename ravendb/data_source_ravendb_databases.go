@@ -0,0 +1,119 @@
+package ravendb
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/ravendb/terraform-provider-ravendb/operations"
+	"sort"
+)
+
+const errorDatabasesRead = "error reading RavenDB databases: %s"
+
+func dataSourceRavendbDatabases() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceDatabasesRead,
+
+		Schema: withConnectionSchema(map[string]*schema.Schema{
+			"names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The names of all the databases of the cluster, sorted.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"databases": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "All the databases of the cluster, ordered by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"encrypted": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"disabled": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"replication_factor": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"nodes": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The tags of the nodes the database is on, members first, then promotables and rehabs.",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"indexes_count": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"load_error": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		}),
+	}
+}
+
+func dataSourceDatabasesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	store, err := getStoreFromData(d, meta, "")
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorDatabasesRead, err.Error()))
+	}
+
+	operation := operations.OperationGetDatabases{}
+	err = executeWithRetries(store, &operation)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorDatabasesRead, err.Error()))
+	}
+
+	sort.Slice(operation.Result, func(i, j int) bool {
+		return operation.Result[i].Name < operation.Result[j].Name
+	})
+	names := make([]interface{}, 0, len(operation.Result))
+	databases := make([]interface{}, 0, len(operation.Result))
+	for _, database := range operation.Result {
+		var nodes []string
+		for _, group := range [][]operations.DatabaseTopologyNode{database.NodesTopology.Members, database.NodesTopology.Promotables, database.NodesTopology.Rehabs} {
+			for _, node := range group {
+				nodes = append(nodes, node.NodeTag)
+			}
+		}
+		names = append(names, database.Name)
+		databases = append(databases, map[string]interface{}{
+			"name":               database.Name,
+			"encrypted":          database.IsEncrypted,
+			"disabled":           database.Disabled,
+			"replication_factor": database.ReplicationFactor,
+			"nodes":              nodes,
+			"indexes_count":      database.IndexesCount,
+			"load_error":         database.LoadError,
+		})
+	}
+
+	err = d.Set("names", names)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorDatabasesRead, err.Error()))
+	}
+	err = d.Set("databases", databases)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorDatabasesRead, err.Error()))
+	}
+	d.SetId("databases")
+
+	return nil
+}
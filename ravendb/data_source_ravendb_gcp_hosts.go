@@ -0,0 +1,224 @@
+package ravendb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	errorGcpHostsRead = "error discovering RavenDB hosts on GCP: %s"
+	gcpComputeUrl     = "https://compute.googleapis.com/compute/v1"
+	gcpMetadataToken  = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+type gcpInstance struct {
+	Name              string            `json:"name"`
+	SelfLink          string            `json:"selfLink"`
+	Status            string            `json:"status"`
+	Labels            map[string]string `json:"labels"`
+	NetworkInterfaces []struct {
+		NetworkIP     string `json:"networkIP"`
+		AccessConfigs []struct {
+			NatIP string `json:"natIP"`
+		} `json:"accessConfigs"`
+	} `json:"networkInterfaces"`
+}
+
+func dataSourceRavendbGcpHosts() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceGcpHostsRead,
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.MultiEnvDefaultFunc([]string{"GOOGLE_PROJECT", "GOOGLE_CLOUD_PROJECT", "CLOUDSDK_CORE_PROJECT"}, nil),
+			},
+			"zone": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"access_token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("GOOGLE_OAUTH_ACCESS_TOKEN", nil),
+				Description: "An OAuth2 access token, e.g. from the google_client_config data source. The metadata server is used when omitted.",
+			},
+			"instance_group": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only instances of this (managed or unmanaged) instance group are returned.",
+			},
+			"labels": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Only instances having all of these labels are returned.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"use_private_ip": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"instance_ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"hosts": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The ip addresses of the running instances, ordered by instance name.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceGcpHostsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	project := d.Get("project").(string)
+	if project == "" {
+		return diag.FromErr(fmt.Errorf(errorGcpHostsRead, "project (or GOOGLE_PROJECT) must be set"))
+	}
+	zone := d.Get("zone").(string)
+
+	token := d.Get("access_token").(string)
+	if token == "" {
+		var err error
+		token, err = gcpMetadataAccessToken(ctx)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf(errorGcpHostsRead, err.Error()))
+		}
+	}
+
+	zoneUrl := gcpComputeUrl + "/projects/" + project + "/zones/" + zone
+	instances, err := gcpListInstances(ctx, token, zoneUrl+"/instances")
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorGcpHostsRead, err.Error()))
+	}
+
+	var members map[string]bool
+	if group, ok := d.GetOk("instance_group"); ok {
+		members, err = gcpInstanceGroupMembers(ctx, token, zoneUrl+"/instanceGroups/"+group.(string)+"/listInstances")
+		if err != nil {
+			return diag.FromErr(fmt.Errorf(errorGcpHostsRead, err.Error()))
+		}
+	}
+
+	labels := d.Get("labels").(map[string]interface{})
+	usePrivateIp := d.Get("use_private_ip").(bool)
+	hosts := map[string]string{}
+	for _, instance := range instances {
+		if instance.Status != "RUNNING" || hasTags(instance.Labels, labels) == false || len(instance.NetworkInterfaces) == 0 {
+			continue
+		}
+		if members != nil && members[instance.SelfLink] == false {
+			continue
+		}
+		nic := instance.NetworkInterfaces[0]
+		if usePrivateIp {
+			hosts[instance.Name] = nic.NetworkIP
+		} else if len(nic.AccessConfigs) > 0 && nic.AccessConfigs[0].NatIP != "" {
+			hosts[instance.Name] = nic.AccessConfigs[0].NatIP
+		}
+	}
+
+	names := make([]string, 0, len(hosts))
+	for name := range hosts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	ips := make([]string, len(names))
+	for i, name := range names {
+		ips[i] = hosts[name]
+	}
+
+	return setDiscoveredHosts(d, "gcp/"+project+"/"+zone+"/"+strings.Join(names, ","), names, ips)
+}
+
+func gcpMetadataAccessToken(ctx context.Context) (string, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, gcpMetadataToken, nil)
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Metadata-Flavor", "Google")
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	err = doJsonRequest(request, &token)
+	if err != nil {
+		return "", errors.New("access_token is not set and the metadata server is not available: " + err.Error())
+	}
+	return token.AccessToken, nil
+}
+
+func gcpListInstances(ctx context.Context, token string, link string) ([]gcpInstance, error) {
+	var instances []gcpInstance
+	pageToken := ""
+	for {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, link+"?pageToken="+url.QueryEscape(pageToken), nil)
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set("Authorization", "Bearer "+token)
+
+		var page struct {
+			Items         []gcpInstance `json:"items"`
+			NextPageToken string        `json:"nextPageToken"`
+		}
+		err = doJsonRequest(request, &page)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, page.Items...)
+		if page.NextPageToken == "" {
+			return instances, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+func gcpInstanceGroupMembers(ctx context.Context, token string, link string) (map[string]bool, error) {
+	members := map[string]bool{}
+	pageToken := ""
+	for {
+		request, err := http.NewRequestWithContext(ctx, http.MethodPost, link+"?pageToken="+url.QueryEscape(pageToken), strings.NewReader(`{"instanceState":"RUNNING"}`))
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set("Authorization", "Bearer "+token)
+		request.Header.Set("Content-Type", "application/json")
+
+		var page struct {
+			Items []struct {
+				Instance string `json:"instance"`
+			} `json:"items"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		err = doJsonRequest(request, &page)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range page.Items {
+			members[item.Instance] = true
+		}
+		if page.NextPageToken == "" {
+			return members, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
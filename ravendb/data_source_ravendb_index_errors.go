@@ -0,0 +1,130 @@
+package ravendb
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/ravendb/terraform-provider-ravendb/operations"
+	"sort"
+)
+
+const errorIndexErrorsRead = "error reading RavenDB index errors: %s"
+
+func dataSourceRavendbIndexErrors() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceIndexErrorsRead,
+
+		Schema: withConnectionSchema(map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"index_names": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Only reads the errors of these indexes. All the indexes of the database are read when unset.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"errors_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The errors of all the indexes together.",
+			},
+			"erroring_indexes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The names of the indexes that have errors, sorted.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"errors": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The errors, ordered by index name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"index": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"timestamp": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"document": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The id of the document that failed to be indexed.",
+						},
+						"action": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "e.g. Map or Reduce.",
+						},
+						"error": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		}),
+	}
+}
+
+func dataSourceIndexErrorsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	database := d.Get("database").(string)
+	store, err := getStoreFromData(d, meta, database)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorIndexErrorsRead, err.Error()))
+	}
+
+	operation := operations.OperationGetIndexErrors{Database: database}
+	for _, name := range d.Get("index_names").([]interface{}) {
+		operation.Names = append(operation.Names, name.(string))
+	}
+	err = executeWithRetries(store, &operation)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorIndexErrorsRead, err.Error()))
+	}
+
+	sort.Slice(operation.Result, func(i, j int) bool {
+		return operation.Result[i].Name < operation.Result[j].Name
+	})
+	erroringIndexes := make([]interface{}, 0)
+	errors := make([]interface{}, 0)
+	for _, index := range operation.Result {
+		if len(index.Errors) == 0 {
+			continue
+		}
+		erroringIndexes = append(erroringIndexes, index.Name)
+		for _, indexError := range index.Errors {
+			errors = append(errors, map[string]interface{}{
+				"index":     index.Name,
+				"timestamp": indexError.Timestamp,
+				"document":  indexError.Document,
+				"action":    indexError.Action,
+				"error":     indexError.Error,
+			})
+		}
+	}
+
+	values := map[string]interface{}{
+		"errors_count":     len(errors),
+		"erroring_indexes": erroringIndexes,
+		"errors":           errors,
+	}
+	for key, value := range values {
+		err = d.Set(key, value)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf(errorIndexErrorsRead, err.Error()))
+		}
+	}
+	d.SetId(database + "/index-errors")
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package ravendb
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/ravendb/terraform-provider-ravendb/operations"
+	"sort"
+)
+
+const errorIndexesRead = "error reading RavenDB indexes: %s"
+
+func dataSourceRavendbIndexes() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceIndexesRead,
+
+		Schema: withConnectionSchema(map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The names of all the indexes of the database, sorted.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"indexes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The definitions of all the indexes of the database, ordered by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "e.g. Map, MapReduce, AutoMap or AutoMapReduce. Auto indexes are created by the server for queries.",
+						},
+						"maps": {
+							Type:     schema.TypeSet,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"reduce": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"configuration": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"output_reduce_to_collection": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"pattern_for_output_reduce_to_collection_references": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"pattern_references_collection_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		}),
+	}
+}
+
+func dataSourceIndexesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	database := d.Get("database").(string)
+	store, err := getStoreFromData(d, meta, database)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorIndexesRead, err.Error()))
+	}
+
+	operation := operations.OperationGetIndexes{Database: database}
+	err = executeWithRetries(store, &operation)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorIndexesRead, err.Error()))
+	}
+
+	sort.Slice(operation.Result, func(i, j int) bool {
+		return operation.Result[i].Name < operation.Result[j].Name
+	})
+	names := make([]interface{}, 0, len(operation.Result))
+	indexes := make([]interface{}, 0, len(operation.Result))
+	for _, index := range operation.Result {
+		names = append(names, index.Name)
+		indexes = append(indexes, map[string]interface{}{
+			"name":                        index.Name,
+			"type":                        index.Type,
+			"maps":                        index.Maps,
+			"reduce":                      index.Reduce,
+			"configuration":               index.Configuration,
+			"output_reduce_to_collection": index.OutputReduceToCollection,
+			"pattern_for_output_reduce_to_collection_references": index.PatternForOutputReduceToCollectionReferences,
+			"pattern_references_collection_name":                 index.PatternReferencesCollectionName,
+		})
+	}
+
+	err = d.Set("names", names)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorIndexesRead, err.Error()))
+	}
+	err = d.Set("indexes", indexes)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorIndexesRead, err.Error()))
+	}
+	d.SetId(database + "/indexes")
+
+	return nil
+}
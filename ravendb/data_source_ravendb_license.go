@@ -0,0 +1,153 @@
+package ravendb
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/ravendb/terraform-provider-ravendb/operations"
+	"sort"
+)
+
+const errorLicenseRead = "error reading RavenDB license utilization: %s"
+
+func dataSourceRavendbLicense() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceLicenseRead,
+
+		Schema: withConnectionSchema(map[string]*schema.Schema{
+			"type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"expiration": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"expired": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"max_cores": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"utilized_cores": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The cores assigned to all the nodes together.",
+			},
+			"available_cores": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"max_cluster_size": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"cluster_size": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"available_nodes": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "How many nodes can still be added to the cluster.",
+			},
+			"nodes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The cores of every node, ordered by tag.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"tag": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"utilized_cores": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"max_utilized_cores": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The limit set on the cores the node may use, 0 when there is none.",
+						},
+						"number_of_cores": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The cores of the machine.",
+						},
+					},
+				},
+			},
+		}),
+	}
+}
+
+func dataSourceLicenseRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	store, err := getStoreFromData(d, meta, "")
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorLicenseRead, err.Error()))
+	}
+
+	status := operations.OperationGetLicenseStatus{}
+	err = executeWithRetries(store, &status)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorLicenseRead, err.Error()))
+	}
+	details := operations.OperationGetNodeLicenseDetails{}
+	err = executeWithRetries(store, &details)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorLicenseRead, err.Error()))
+	}
+
+	tags := make([]string, 0, len(details.Result))
+	for tag := range details.Result {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	utilizedCores := 0
+	nodes := make([]interface{}, 0, len(tags))
+	for _, tag := range tags {
+		node := details.Result[tag]
+		maxUtilizedCores := 0
+		if node.MaxUtilizedCores != nil {
+			maxUtilizedCores = *node.MaxUtilizedCores
+		}
+		utilizedCores += node.UtilizedCores
+		nodes = append(nodes, map[string]interface{}{
+			"tag":                tag,
+			"utilized_cores":     node.UtilizedCores,
+			"max_utilized_cores": maxUtilizedCores,
+			"number_of_cores":    node.NumberOfCores,
+		})
+	}
+
+	expiration := ""
+	if status.Result.Expiration != nil {
+		expiration = *status.Result.Expiration
+	}
+	values := map[string]interface{}{
+		"type":             status.Result.Type,
+		"expiration":       expiration,
+		"expired":          status.Result.Expired,
+		"max_cores":        status.Result.MaxCores,
+		"utilized_cores":   utilizedCores,
+		"available_cores":  status.Result.MaxCores - utilizedCores,
+		"max_cluster_size": status.Result.MaxClusterSize,
+		"cluster_size":     len(tags),
+		"available_nodes":  status.Result.MaxClusterSize - len(tags),
+		"nodes":            nodes,
+	}
+	for key, value := range values {
+		err = d.Set(key, value)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf(errorLicenseRead, err.Error()))
+		}
+	}
+	d.SetId("license")
+
+	return nil
+}
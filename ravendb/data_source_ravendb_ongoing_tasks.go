@@ -0,0 +1,123 @@
+package ravendb
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/ravendb/terraform-provider-ravendb/operations"
+	"sort"
+)
+
+const errorOngoingTasksRead = "error reading RavenDB ongoing tasks: %s"
+
+var ongoingTaskTypes = []string{"Replication", "RavenEtl", "SqlEtl", "OlapEtl", "ElasticSearchEtl", "QueueEtl", "QueueSink", "Backup", "Subscription", "PullReplicationAsHub", "PullReplicationAsSink"}
+
+func dataSourceRavendbOngoingTasks() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceOngoingTasksRead,
+
+		Schema: withConnectionSchema(map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"types": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Only return tasks of these types.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(ongoingTaskTypes, false),
+				},
+			},
+			"tasks": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The ongoing tasks of the database, ordered by task id.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"task_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"state": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Enabled, Disabled or PartiallyEnabled.",
+						},
+						"connection_status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"responsible_node": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The tag of the node running the task.",
+						},
+						"mentor_node": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"error": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		}),
+	}
+}
+
+func dataSourceOngoingTasksRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	database := d.Get("database").(string)
+	store, err := getStoreFromData(d, meta, database)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorOngoingTasksRead, err.Error()))
+	}
+
+	operation := operations.OperationGetOngoingTasks{Database: database}
+	err = executeWithRetries(store, &operation)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorOngoingTasksRead, err.Error()))
+	}
+
+	types := d.Get("types").(*schema.Set)
+	sort.Slice(operation.Result, func(i, j int) bool {
+		return operation.Result[i].TaskId < operation.Result[j].TaskId
+	})
+	tasks := make([]interface{}, 0, len(operation.Result))
+	for _, task := range operation.Result {
+		if types.Len() > 0 && !types.Contains(task.TaskType) {
+			continue
+		}
+		tasks = append(tasks, map[string]interface{}{
+			"task_id":           int(task.TaskId),
+			"type":              task.TaskType,
+			"name":              task.TaskName,
+			"state":             task.TaskState,
+			"connection_status": task.TaskConnectionStatus,
+			"responsible_node":  task.ResponsibleNode.NodeTag,
+			"mentor_node":       task.MentorNode,
+			"error":             task.Error,
+		})
+	}
+
+	err = d.Set("tasks", tasks)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorOngoingTasksRead, err.Error()))
+	}
+	d.SetId(database + "/tasks")
+
+	return nil
+}
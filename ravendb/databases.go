@@ -0,0 +1,812 @@
+package ravendb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/ravendb/ravendb-go-client"
+	"github.com/ravendb/terraform-provider-ravendb/operations"
+	"reflect"
+	"sync"
+	"time"
+)
+
+const INDEX_WORKERS int = 4
+
+const (
+	INDEX_STATE_NORMAL   string = "normal"
+	INDEX_STATE_DISABLED string = "disabled"
+	INDEX_STATE_PAUSED   string = "paused"
+)
+
+type Database struct {
+	Name              string
+	ReplicationFactor int
+	Settings          map[string]string
+	CreateSampleData  bool
+	Disabled          bool
+	ReloadOnChange    bool
+	Protected         bool
+	// ReplicationTimeout is how long to wait for every member to be online and caught up, 0 doesn't wait
+	ReplicationTimeout time.Duration
+	Indexes            []Index
+}
+
+type Index struct {
+	Name                            string
+	Maps                            []string
+	Reduce                          string
+	Configuration                   map[string]string
+	OutputReduceToCollection        string
+	PatternForReferences            string
+	PatternReferencesCollectionName string
+	ResetOnChange                   bool
+	SideBySide                      bool
+	SwapTimeout                     time.Duration
+	State                           string
+}
+
+type HealthcheckDatabase struct {
+	Encrypted         bool
+	NamePrefix        string
+	ReplicationFactor int
+}
+
+func healthcheckDatabaseSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Controls how the healthcheck database set by database is created.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"encrypted": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Description: "Creates the database encrypted, with a key generated and distributed to the nodes by the server. Requires a secured cluster.",
+				},
+				"name_prefix": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "A prefix prepended to the database name, e.g. to follow a naming policy for encrypted databases.",
+				},
+				"replication_factor": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      1,
+					Description:  "The number of nodes the database is replicated to.",
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+func parseHealthcheckDatabase(d *schema.ResourceData) HealthcheckDatabase {
+	return healthcheckDatabaseFromSet(d.Get("healthcheck_database").(*schema.Set))
+}
+
+func healthcheckDatabaseFromSet(set *schema.Set) HealthcheckDatabase {
+	for _, v := range set.List() {
+		value := v.(map[string]interface{})
+		return HealthcheckDatabase{
+			Encrypted:         value["encrypted"].(bool),
+			NamePrefix:        value["name_prefix"].(string),
+			ReplicationFactor: value["replication_factor"].(int),
+		}
+	}
+	return HealthcheckDatabase{ReplicationFactor: 1}
+}
+
+// customizeHealthcheckDatabase refuses to change the encryption of an existing healthcheck database, as a database
+// can only be encrypted when it is created.
+func customizeHealthcheckDatabase(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || d.HasChange("healthcheck_database") == false {
+		return nil
+	}
+	oldName, newName := d.GetChange("database")
+	oldSet, newSet := d.GetChange("healthcheck_database")
+	before := healthcheckDatabaseFromSet(oldSet.(*schema.Set))
+	after := healthcheckDatabaseFromSet(newSet.(*schema.Set))
+	if oldName.(string) == "" || before.Encrypted == after.Encrypted || before.NamePrefix+oldName.(string) != after.NamePrefix+newName.(string) {
+		return nil
+	}
+	return errors.New("healthcheck_database.encrypted only applies when the database is created and can't be changed for the existing " +
+		before.NamePrefix + oldName.(string) + " database, change database or name_prefix to have a new one created")
+}
+
+func databasesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		Description: "The databases (and their indexes) to create once the cluster is up.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"replication_factor": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Description:  "The number of nodes the database is replicated to. Defaults to all the nodes.",
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"settings": {
+					Type:     schema.TypeMap,
+					Optional: true,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+				"create_sample_data": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Description: "Fills the database with the Northwind sample data when it is created.",
+				},
+				"disabled": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Description: "Parks the database. A disabled database keeps its data but rejects any request until it is enabled again.",
+				},
+				"reload_on_settings_change": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     true,
+					Description: "Reloads an existing database when its settings change, as settings only take effect when the database is loaded.",
+				},
+				"protected": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Description: "Locks the database against deletion. It has to be unset, and applied, before the database can be removed.",
+				},
+				"wait_for_replication": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Description: "Waits until the database group has all its members online and caught up before the apply completes.",
+				},
+				"replication_timeout_sec": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      600,
+					Description:  "How long wait_for_replication waits.",
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"indexes": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"name": {
+								Type:     schema.TypeString,
+								Required: true,
+							},
+							"maps": {
+								Type:     schema.TypeList,
+								Required: true,
+								MinItems: 1,
+								Elem: &schema.Schema{
+									Type: schema.TypeString,
+								},
+							},
+							"reduce": {
+								Type:     schema.TypeString,
+								Optional: true,
+							},
+							"configuration": {
+								Type:     schema.TypeMap,
+								Optional: true,
+								Elem: &schema.Schema{
+									Type: schema.TypeString,
+								},
+							},
+							"output_reduce_to_collection": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "The collection the results of a map-reduce index are written to as artificial documents.",
+							},
+							"pattern_for_output_reduce_to_collection_references": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "The pattern of the ids of the reference documents created for the artificial documents, e.g. reports/daily/{OrderedAt:yyyy-MM-dd}.",
+							},
+							"pattern_references_collection_name": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "The collection of the reference documents. Defaults to <output_reduce_to_collection>/References.",
+							},
+							"reset_on_change": {
+								Type:        schema.TypeBool,
+								Optional:    true,
+								Description: "Resets the index after its definition is updated, so no results of the previous definition are kept.",
+							},
+							"side_by_side": {
+								Type:        schema.TypeBool,
+								Optional:    true,
+								Default:     true,
+								Description: "Keeps serving the previous definition of an updated index until the new one is not stale. When false the new definition is swapped in right away.",
+							},
+							"swap_timeout_sec": {
+								Type:         schema.TypeInt,
+								Optional:     true,
+								Description:  "How long to wait for a side by side index to be swapped in. The swap is not waited for when unset.",
+								ValidateFunc: validation.IntAtLeast(0),
+							},
+							"state": {
+								Type:         schema.TypeString,
+								Optional:     true,
+								Default:      INDEX_STATE_NORMAL,
+								Description:  "normal, disabled or paused. A paused index stops indexing on every node until it is set back to normal, or the node restarts.",
+								ValidateFunc: validation.StringInSlice([]string{INDEX_STATE_NORMAL, INDEX_STATE_DISABLED, INDEX_STATE_PAUSED}, false),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// ignoreEmbeddedDatabasesSchema lets the databases be managed outside of the resource, so the databases block can
+// be removed without deleting them.
+func ignoreEmbeddedDatabasesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "Ignores the databases block: its databases and indexes are neither created, changed nor deleted, so they can be moved to other resources without being recreated.",
+	}
+}
+
+// hardDeleteDatabasesSchema opts into deleting the data files of the databases removed from the databases block.
+func hardDeleteDatabasesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "Deletes the data files of the databases removed from the databases block. By default the databases are removed from the cluster and their files are kept on the nodes.",
+	}
+}
+
+func parseDatabases(d *schema.ResourceData) []Database {
+	if d.Get("ignore_embedded_databases").(bool) {
+		return nil
+	}
+	list := d.Get("databases").([]interface{})
+	databases := make([]Database, len(list))
+	for i, v := range list {
+		value := v.(map[string]interface{})
+		databases[i] = Database{
+			Name:              value["name"].(string),
+			ReplicationFactor: value["replication_factor"].(int),
+			Settings:          toStringMap(value["settings"].(map[string]interface{})),
+			CreateSampleData:  value["create_sample_data"].(bool),
+			Disabled:          value["disabled"].(bool),
+			ReloadOnChange:    value["reload_on_settings_change"].(bool),
+			Protected:         value["protected"].(bool),
+		}
+		if value["wait_for_replication"].(bool) {
+			databases[i].ReplicationTimeout = time.Duration(value["replication_timeout_sec"].(int)) * time.Second
+		}
+		for _, idx := range value["indexes"].([]interface{}) {
+			index := idx.(map[string]interface{})
+			maps := index["maps"].([]interface{})
+			parsed := Index{
+				Name:                            index["name"].(string),
+				Maps:                            make([]string, len(maps)),
+				Reduce:                          index["reduce"].(string),
+				Configuration:                   toStringMap(index["configuration"].(map[string]interface{})),
+				OutputReduceToCollection:        index["output_reduce_to_collection"].(string),
+				PatternForReferences:            index["pattern_for_output_reduce_to_collection_references"].(string),
+				PatternReferencesCollectionName: index["pattern_references_collection_name"].(string),
+				ResetOnChange:                   index["reset_on_change"].(bool),
+				SideBySide:                      index["side_by_side"].(bool),
+				SwapTimeout:                     time.Duration(index["swap_timeout_sec"].(int)) * time.Second,
+				State:                           index["state"].(string),
+			}
+			for j, m := range maps {
+				parsed.Maps[j] = m.(string)
+			}
+			databases[i].Indexes = append(databases[i].Indexes, parsed)
+		}
+	}
+	return databases
+}
+
+func toStringMap(m map[string]interface{}) map[string]string {
+	result := make(map[string]string, len(m))
+	for key, value := range m {
+		result[key] = value.(string)
+	}
+	return result
+}
+
+func (sc *ServerConfig) createDatabases(store *ravendb.DocumentStore) error {
+	var reload []string
+	for _, database := range sc.Databases {
+		replicationFactor := database.ReplicationFactor
+		if replicationFactor == 0 {
+			replicationFactor = len(sc.Hosts)
+		}
+		err := executeWithRetries(store,
+			ravendb.NewCreateDatabaseOperation(&ravendb.DatabaseRecord{
+				DatabaseName: database.Name,
+				Settings:     database.Settings,
+			}, replicationFactor))
+
+		if err != nil && reflect.TypeOf(err) != reflect.TypeOf(&ravendb.ConcurrencyError{}) {
+			return err
+		}
+
+		// the database already exists
+		if err != nil {
+			changed, err := updateDatabaseSettings(store, database)
+			if err != nil {
+				return err
+			}
+			if changed && database.ReloadOnChange && database.Disabled == false {
+				reload = append(reload, database.Name)
+			}
+			continue
+		}
+
+		// sample data can only go into an empty database, so it is only created along with the database
+		if database.CreateSampleData {
+			err = executeWithRetries(store, &operations.OperationCreateSampleData{Database: database.Name})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err := reloadDatabases(store, reload)
+	if err != nil {
+		return err
+	}
+
+	err = sc.lockDatabases(store)
+	if err != nil {
+		return err
+	}
+
+	// the indexes of a disabled database can't be deployed, they are deployed by the apply enabling it
+	err = sc.toggleDatabases(store, false)
+	if err != nil {
+		return err
+	}
+	err = sc.createIndexes(store)
+	if err != nil {
+		return err
+	}
+	err = sc.toggleDatabases(store, true)
+	if err != nil {
+		return err
+	}
+	return sc.waitForReplication(store)
+}
+
+// waitForReplication waits for the databases set to wait_for_replication to have as many members as their
+// replication factor, all reporting an Ok status, and no node still catching up as a promotable or in rehab.
+func (sc *ServerConfig) waitForReplication(store *ravendb.DocumentStore) error {
+	for _, database := range sc.Databases {
+		if database.ReplicationTimeout == 0 || database.Disabled {
+			continue
+		}
+		replicationFactor := database.ReplicationFactor
+		if replicationFactor == 0 {
+			replicationFactor = len(sc.Hosts)
+		}
+		err := waitFor("replication of "+database.Name, database.ReplicationTimeout, func() (bool, error) {
+			databases := operations.OperationGetDatabases{}
+			err := executeWithRetries(store, &databases)
+			if err != nil {
+				return false, err
+			}
+			for _, info := range databases.Result {
+				if info.Name != database.Name {
+					continue
+				}
+				topology := info.NodesTopology
+				if len(topology.Promotables) > 0 || len(topology.Rehabs) > 0 || len(topology.Members) < replicationFactor {
+					return false, fmt.Errorf("%d of %d members, %d promotables and %d in rehab", len(topology.Members), replicationFactor, len(topology.Promotables), len(topology.Rehabs))
+				}
+				for _, member := range topology.Members {
+					if status := topology.Status[member.NodeTag]; status.LastStatus != "Ok" {
+						return false, errors.New("node " + member.NodeTag + " is not online: " + status.LastStatus + " " + status.LastError)
+					}
+				}
+				return true, nil
+			}
+			return false, errors.New("the database does not exist")
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateDatabaseSettings replaces the settings in the record of an existing database, and reports
+// whether they were changed.
+func updateDatabaseSettings(store *ravendb.DocumentStore, database Database) (bool, error) {
+	record := operations.OperationGetDatabaseRecord{Database: database.Name}
+	err := executeWithRetries(store, &record)
+	if err != nil {
+		return false, err
+	}
+	if record.Result == nil {
+		return false, errors.New("database " + database.Name + " does not exist")
+	}
+
+	current := map[string]string{}
+	if settings, ok := record.Result["Settings"].(map[string]interface{}); ok {
+		for key, value := range settings {
+			current[key] = fmt.Sprintf("%v", value)
+		}
+	}
+	if reflect.DeepEqual(current, database.Settings) {
+		return false, nil
+	}
+
+	record.Result["Settings"] = database.Settings
+	err = executeWithRetries(store, &operations.OperationUpdateDatabaseRecord{Record: record.Result})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// staleDatabaseRecord reports whether err rejects a database record update, as the record was modified since it was
+// read. Sending the same record again won't change that.
+func staleDatabaseRecord(operation ravendb.IServerOperation, err error) bool {
+	var errConcurrency *ravendb.ConcurrencyError
+	_, update := operation.(*operations.OperationUpdateDatabaseRecord)
+	return update && errors.As(err, &errConcurrency)
+}
+
+// reloadDatabases disables and enables databases again, so changed settings take effect.
+func reloadDatabases(store *ravendb.DocumentStore, databases []string) error {
+	if len(databases) == 0 {
+		return nil
+	}
+	err := executeWithRetries(store, &operations.OperationToggleDatabasesState{Databases: databases, Disable: true})
+	if err != nil {
+		return err
+	}
+	return executeWithRetries(store, &operations.OperationToggleDatabasesState{Databases: databases, Disable: false})
+}
+
+func (sc *ServerConfig) lockDatabases(store *ravendb.DocumentStore) error {
+	modes := map[string][]string{}
+	for _, database := range sc.Databases {
+		mode := operations.DatabaseLockModeUnlock
+		if database.Protected {
+			mode = operations.DatabaseLockModePreventDeletesError
+		}
+		modes[mode] = append(modes[mode], database.Name)
+	}
+	for mode, names := range modes {
+		err := executeWithRetries(store, &operations.OperationSetDatabasesLock{
+			Databases: names,
+			Mode:      mode,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteDatabases deletes the databases that were removed from the configuration, along with their data files
+// when hardDelete is set. Protected databases are refused, as they have to be unlocked by an apply that unsets
+// protected first.
+func deleteDatabases(store *ravendb.DocumentStore, names []string, hardDelete bool) error {
+	for _, name := range names {
+		record := operations.OperationGetDatabaseRecord{Database: name}
+		err := executeWithRetries(store, &record)
+		if err != nil {
+			return err
+		}
+		if record.Result == nil {
+			continue
+		}
+		if mode, ok := record.Result["LockMode"].(string); ok && mode != "" && mode != operations.DatabaseLockModeUnlock {
+			return errors.New("database " + name + " is protected and can't be deleted, set protected = false and apply before removing it")
+		}
+	}
+	for _, name := range names {
+		err := executeWithRetries(store, ravendb.NewDeleteDatabasesOperation(name, hardDelete))
+		if err != nil && reflect.TypeOf(err) != reflect.TypeOf(&ravendb.DatabaseDoesNotExistError{}) {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteRemovedDatabases deletes the databases removed from the databases block by the current change, unless
+// the block is ignored.
+func (sc *ServerConfig) deleteRemovedDatabases(d *schema.ResourceData) error {
+	if d.Get("ignore_embedded_databases").(bool) {
+		return nil
+	}
+	removed := removedDatabases(d)
+	if len(removed) == 0 {
+		return nil
+	}
+	store, err := getStore(sc, 0)
+	if err != nil {
+		return err
+	}
+	return deleteDatabases(store, removed, d.Get("hard_delete_databases").(bool))
+}
+
+// deployIndexes puts all the indexes of a database with a single call, then resets the indexes with
+// reset_on_change whose definition was changed by it.
+func deployIndexes(store *ravendb.DocumentStore, database Database) error {
+	var changed []string
+	definitions := make([]operations.IndexDefinition, len(database.Indexes))
+	for j, index := range database.Indexes {
+		definitions[j] = operations.IndexDefinition{
+			Name:                     index.Name,
+			Maps:                     index.Maps,
+			Reduce:                   index.Reduce,
+			Configuration:            index.Configuration,
+			OutputReduceToCollection: index.OutputReduceToCollection,
+			PatternForOutputReduceToCollectionReferences: index.PatternForReferences,
+			PatternReferencesCollectionName:              index.PatternReferencesCollectionName,
+		}
+		if index.ResetOnChange == false {
+			continue
+		}
+		current := operations.OperationGetIndex{Database: database.Name, Name: index.Name}
+		err := executeWithRetries(store, &current)
+		if err != nil {
+			return err
+		}
+		if current.Result != nil && sameIndexDefinition(*current.Result, definitions[j]) == false {
+			changed = append(changed, index.Name)
+		}
+	}
+
+	err := executeWithRetries(store, &operations.OperationPutIndexes{
+		Database: database.Name,
+		Indexes:  definitions,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, index := range database.Indexes {
+		err = swapIndex(store, database.Name, index)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range changed {
+		err = executeWithRetries(store, &operations.OperationResetIndex{Database: database.Name, Name: name})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// swapIndex handles the side by side replacement the server creates when an existing index is updated. It is
+// swapped in right away unless side_by_side is set, in which case the swap is waited for up to swap_timeout_sec.
+// A replacement still running after that is left to be swapped by the server, and reported by indexSwapStatus.
+func swapIndex(store *ravendb.DocumentStore, database string, index Index) error {
+	if index.SideBySide && index.SwapTimeout == 0 {
+		return nil
+	}
+	replacement, err := hasIndexReplacement(store, database, index.Name)
+	if err != nil || replacement == false {
+		return err
+	}
+	if index.SideBySide == false {
+		return executeWithRetries(store, &operations.OperationReplaceIndex{Database: database, Name: index.Name})
+	}
+
+	deadline := time.Now().Add(index.SwapTimeout)
+	for replacement && time.Now().Before(deadline) {
+		time.Sleep(2 * time.Second)
+		replacement, err = hasIndexReplacement(store, database, index.Name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hasIndexReplacement(store *ravendb.DocumentStore, database string, name string) (bool, error) {
+	replacement := operations.OperationGetIndex{Database: database, Name: operations.IndexReplacementPrefix + name}
+	err := executeWithRetries(store, &replacement)
+	if err != nil {
+		return false, err
+	}
+	return replacement.Result != nil, nil
+}
+
+func indexSwapStatusSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeMap,
+		Computed:    true,
+		Description: "Whether a side by side replacement is pending for every index, by database/index, so CI can gate on the swap.",
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+}
+
+// setIndexSwapStatus stores the index_swap_status of the databases of sc.
+func (sc *ServerConfig) setIndexSwapStatus(d *schema.ResourceData) error {
+	status := map[string]string{}
+	if len(sc.Databases) > 0 {
+		store, err := getStore(sc, 0)
+		if err != nil {
+			return err
+		}
+		status, err = sc.indexSwapStatus(store)
+		if err != nil {
+			return err
+		}
+	}
+	return d.Set("index_swap_status", status)
+}
+
+// indexSwapStatus reports, by database/index, whether a side by side replacement of the index is "pending" or
+// there is "none".
+func (sc *ServerConfig) indexSwapStatus(store *ravendb.DocumentStore) (map[string]string, error) {
+	status := map[string]string{}
+	for _, database := range sc.Databases {
+		if database.Disabled {
+			continue
+		}
+		for _, index := range database.Indexes {
+			replacement, err := hasIndexReplacement(store, database.Name, index.Name)
+			if err != nil {
+				return nil, err
+			}
+			status[database.Name+"/"+index.Name] = "none"
+			if replacement {
+				status[database.Name+"/"+index.Name] = "pending"
+			}
+		}
+	}
+	return status, nil
+}
+
+// setIndexStates enables or disables the indexes of a database cluster wide, and pauses or resumes them on
+// every node, as pausing an index only applies to the node it is requested from.
+func (sc *ServerConfig) setIndexStates(store *ravendb.DocumentStore, database Database) error {
+	for _, index := range database.Indexes {
+		enable := operations.IndexActionEnable
+		if index.State == INDEX_STATE_DISABLED {
+			enable = operations.IndexActionDisable
+		}
+		err := executeWithRetries(store, &operations.OperationIndexAction{Database: database.Name, Name: index.Name, Action: enable})
+		if err != nil {
+			return err
+		}
+		if index.State == INDEX_STATE_DISABLED {
+			continue
+		}
+
+		start := operations.IndexActionStart
+		if index.State == INDEX_STATE_PAUSED {
+			start = operations.IndexActionStop
+		}
+		for i := range sc.Url.List {
+			nodeStore, err := getStore(sc, i)
+			if err != nil {
+				return err
+			}
+			err = executeWithRetries(nodeStore, &operations.OperationIndexAction{Database: database.Name, Name: index.Name, Action: start})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func sameIndexDefinition(a operations.IndexDefinition, b operations.IndexDefinition) bool {
+	if len(a.Maps) != len(b.Maps) || a.Reduce != b.Reduce ||
+		a.OutputReduceToCollection != b.OutputReduceToCollection ||
+		a.PatternForOutputReduceToCollectionReferences != b.PatternForOutputReduceToCollectionReferences ||
+		a.PatternReferencesCollectionName != b.PatternReferencesCollectionName ||
+		len(a.Configuration) != len(b.Configuration) {
+		return false
+	}
+	for key, value := range a.Configuration {
+		if other, ok := b.Configuration[key]; !ok || other != value {
+			return false
+		}
+	}
+	// the server keeps the maps as a set, so their order is not preserved
+	maps := map[string]bool{}
+	for _, m := range a.Maps {
+		maps[m] = true
+	}
+	for _, m := range b.Maps {
+		if maps[m] == false {
+			return false
+		}
+	}
+	return true
+}
+
+// removedDatabases returns the names of the databases that are in the old value of the databases block
+// but not in the new one.
+func removedDatabases(d *schema.ResourceData) []string {
+	before, after := d.GetChange("databases")
+	kept := map[string]bool{}
+	for _, v := range after.([]interface{}) {
+		kept[v.(map[string]interface{})["name"].(string)] = true
+	}
+	var removed []string
+	for _, v := range before.([]interface{}) {
+		name := v.(map[string]interface{})["name"].(string)
+		if kept[name] == false {
+			removed = append(removed, name)
+		}
+	}
+	return removed
+}
+
+func (sc *ServerConfig) toggleDatabases(store *ravendb.DocumentStore, disable bool) error {
+	var names []string
+	for _, database := range sc.Databases {
+		if database.Disabled == disable {
+			names = append(names, database.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return executeWithRetries(store, &operations.OperationToggleDatabasesState{
+		Databases: names,
+		Disable:   disable,
+	})
+}
+
+// createIndexes deploys the indexes of every database with a single PutIndexes call, and the
+// databases concurrently using a bounded pool of workers. Disabled databases reject the calls, their
+// indexes are deployed once they are enabled again.
+func (sc *ServerConfig) createIndexes(store *ravendb.DocumentStore) error {
+	var wg sync.WaitGroup
+	jobs := make(chan Database)
+	errorsChannel := make(chan error, len(sc.Databases))
+
+	for i := 0; i < INDEX_WORKERS; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for database := range jobs {
+				err := deployIndexes(store, database)
+				if err == nil {
+					err = sc.setIndexStates(store, database)
+				}
+				if err != nil {
+					errorsChannel <- err
+				}
+			}
+		}()
+	}
+
+	for _, database := range sc.Databases {
+		if len(database.Indexes) > 0 && database.Disabled == false {
+			jobs <- database
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	close(errorsChannel)
+
+	var result error
+	for err := range errorsChannel {
+		result = multierror.Append(result, err)
+	}
+	return result
+}
@@ -0,0 +1,49 @@
+package ravendb
+
+import (
+	"github.com/ravendb/ravendb-go-client"
+	"github.com/ravendb/terraform-provider-ravendb/operations"
+	"testing"
+)
+
+func TestSameIndexDefinition(t *testing.T) {
+	definition := operations.IndexDefinition{
+		Maps:                            []string{"from o in docs.Orders select new { o.Company }", "from c in docs.Companies select new { c.Name }"},
+		Configuration:                   map[string]string{"Indexing.MapBatchSize": "1024"},
+		PatternReferencesCollectionName: "OrderReferences",
+	}
+	reordered := definition
+	reordered.Maps = []string{definition.Maps[1], definition.Maps[0]}
+	if !sameIndexDefinition(definition, reordered) {
+		t.Error("expected the order of the maps not to matter")
+	}
+
+	configured := definition
+	configured.Configuration = map[string]string{"Indexing.MapBatchSize": "2048"}
+	if sameIndexDefinition(definition, configured) {
+		t.Error("expected a changed configuration to change the definition")
+	}
+	configured.Configuration = nil
+	if sameIndexDefinition(definition, configured) {
+		t.Error("expected a removed configuration to change the definition")
+	}
+
+	renamed := definition
+	renamed.PatternReferencesCollectionName = "References"
+	if sameIndexDefinition(definition, renamed) {
+		t.Error("expected a changed pattern references collection to change the definition")
+	}
+}
+
+func TestStaleDatabaseRecord(t *testing.T) {
+	update := &operations.OperationUpdateDatabaseRecord{Record: operations.DatabaseRecord{"DatabaseName": "Orders", "Etag": float64(12)}}
+	if !staleDatabaseRecord(update, &ravendb.ConcurrencyError{}) {
+		t.Error("expected a ConcurrencyError answering a record update not to be retried")
+	}
+	if staleDatabaseRecord(update, &ravendb.NoLeaderError{}) {
+		t.Error("expected a NoLeaderError answering a record update to be retried")
+	}
+	if staleDatabaseRecord(&operations.OperationGetDatabaseRecord{Database: "Orders"}, &ravendb.ConcurrencyError{}) {
+		t.Error("expected a ConcurrencyError answering another operation to be retried")
+	}
+}
@@ -0,0 +1,50 @@
+package ravendb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var debugBundleCommands = map[string]string{
+	"journalctl.log": "sudo journalctl -u ravendb --no-pager -n 2000",
+	"status.txt":     "sudo systemctl status ravendb --no-pager",
+	"settings.json":  "sudo cat /etc/ravendb/settings.json",
+	"disk.txt":       "df -h",
+	"memory.txt":     "free -m",
+}
+
+// collectDebugBundle gathers the service logs and host information of a node that failed
+// to deploy into <directory>/<host>-<timestamp> and returns the path it was written to.
+func collectDebugBundle(conn Transport, directory string, publicIP string, output []byte) (string, error) {
+	bundlePath := filepath.Join(directory, publicIP+"-"+time.Now().UTC().Format("20060102T150405Z"))
+	err := os.MkdirAll(bundlePath, 0700)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.WriteFile(filepath.Join(bundlePath, "deploy.log"), output, 0600)
+	if err != nil {
+		return "", err
+	}
+
+	for fileName, cmd := range debugBundleCommands {
+		contents, err := runCommand(conn, cmd)
+		if err != nil {
+			contents = append(contents, []byte("\n"+err.Error()+"\n")...)
+		}
+		err = os.WriteFile(filepath.Join(bundlePath, fileName), contents, 0600)
+		if err != nil {
+			return "", err
+		}
+	}
+	return bundlePath, nil
+}
+
+func withDebugBundle(err error, bundlePath string, bundleErr error) error {
+	if bundleErr != nil {
+		return fmt.Errorf("%w (failed to collect debug bundle: %s)", err, bundleErr.Error())
+	}
+	return fmt.Errorf("%w (debug bundle written to %s)", err, bundlePath)
+}
@@ -0,0 +1,97 @@
+package ravendb
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"strconv"
+	"time"
+)
+
+// DeployRetry sets how many times the install and configure phases of a node are attempted before the node is
+// reported failed.
+type DeployRetry struct {
+	Attempts int
+	Delay    time.Duration
+	Cleanup  bool
+}
+
+func deployRetrySchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Retries the install and configure phases of a node that failed, e.g. on an unreachable package mirror or a slow cloud-init, before the node is reported failed.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"attempts": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      3,
+					Description:  "How many times a phase is attempted on a node, the first attempt included.",
+					ValidateFunc: validation.IntBetween(1, 10),
+				},
+				"delay_sec": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      30,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"cleanup": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     true,
+					Description: "Removes the downloaded package and repairs the package manager state before the install is attempted again.",
+				},
+			},
+		},
+	}
+}
+
+func parseDeployRetry(d *schema.ResourceData) *DeployRetry {
+	for _, v := range d.Get("deploy_retry").(*schema.Set).List() {
+		value := v.(map[string]interface{})
+		return &DeployRetry{
+			Attempts: value["attempts"].(int),
+			Delay:    time.Duration(value["delay_sec"].(int)) * time.Second,
+			Cleanup:  value["cleanup"].(bool),
+		}
+	}
+	return nil
+}
+
+// withRetries wraps a per-host action of forEachHost so it is attempted as set by sc.DeployRetry, running
+// cleanup, when given, between the attempts.
+func (sc *ServerConfig) withRetries(phase string, action func(publicIP string, index int) error, cleanup func(publicIP string) error) func(publicIP string, index int) error {
+	if sc.DeployRetry == nil || sc.DeployRetry.Attempts <= 1 {
+		return action
+	}
+	return func(publicIP string, index int) error {
+		var err error
+		for attempt := 1; ; attempt++ {
+			err = action(publicIP, index)
+			if err == nil || attempt == sc.DeployRetry.Attempts {
+				return err
+			}
+			log.Println("[" + publicIP + "] " + phase + " attempt " + strconv.Itoa(attempt) + " of " + strconv.Itoa(sc.DeployRetry.Attempts) + " failed, retrying: " + err.Error())
+			sc.report.retried(publicIP)
+			time.Sleep(sc.DeployRetry.Delay)
+			if cleanup != nil && sc.DeployRetry.Cleanup {
+				if cleanupErr := cleanup(publicIP); cleanupErr != nil {
+					log.Println("[" + publicIP + "] cleanup before the next " + phase + " attempt failed: " + cleanupErr.Error())
+				}
+			}
+		}
+	}
+}
+
+// cleanupInstall leaves a host whose install failed ready for another attempt.
+func (sc *ServerConfig) cleanupInstall(publicIP string) error {
+	return sc.onHost(publicIP, func(conn Transport, stdoutBuf *nodeLog) error {
+		family, err := detectOsFamily(conn)
+		if err != nil {
+			return err
+		}
+		return sc.execute(publicIP, cleanupCommands(family), "", stdoutBuf, conn)
+	})
+}
@@ -0,0 +1,137 @@
+package ravendb
+
+import (
+	"encoding/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"sync"
+	"time"
+)
+
+func deploymentReportSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "JSON summary of the last apply: the actions taken on every node with their durations, the installed version and how many times RavenDB was restarted.",
+	}
+}
+
+// deploymentReport records what an apply did. It is shared by the hosts deployed in parallel.
+type deploymentReport struct {
+	mu      sync.Mutex
+	Actions []reportedAction `json:"actions"`
+	Nodes   []*reportedNode  `json:"nodes"`
+	nodes   map[string]*reportedNode
+}
+
+type reportedNode struct {
+	Host     string           `json:"host"`
+	Version  string           `json:"version"`
+	Restarts int              `json:"restarts"`
+	Reboots  int              `json:"reboots"`
+	Retries  int              `json:"retries"`
+	Actions  []reportedAction `json:"actions"`
+}
+
+type reportedAction struct {
+	Action     string `json:"action"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+func newDeploymentReport() *deploymentReport {
+	return &deploymentReport{
+		Actions: []reportedAction{},
+		Nodes:   []*reportedNode{},
+		nodes:   make(map[string]*reportedNode),
+	}
+}
+
+// node returns the entry of host, adding it in the order hosts are first seen. Callers hold r.mu.
+func (r *deploymentReport) node(host string) *reportedNode {
+	node, ok := r.nodes[host]
+	if !ok {
+		node = &reportedNode{Host: host, Actions: []reportedAction{}}
+		r.nodes[host] = node
+		r.Nodes = append(r.Nodes, node)
+	}
+	return node
+}
+
+func newReportedAction(action string, started time.Time, err error) reportedAction {
+	reported := reportedAction{
+		Action:     action,
+		DurationMs: time.Since(started).Milliseconds(),
+	}
+	if err != nil {
+		reported.Error = err.Error()
+	}
+	return reported
+}
+
+// timed runs a cluster wide action and records how long it took.
+func (r *deploymentReport) timed(action string, run func() error) error {
+	started := time.Now()
+	err := run()
+	if r != nil {
+		r.mu.Lock()
+		r.Actions = append(r.Actions, newReportedAction(action, started, err))
+		r.mu.Unlock()
+	}
+	return err
+}
+
+// timedOnHost wraps a per-host action of forEachHost so its duration is recorded for the host.
+func (r *deploymentReport) timedOnHost(action string, run func(publicIP string, index int) error) func(publicIP string, index int) error {
+	return func(publicIP string, index int) error {
+		started := time.Now()
+		err := run(publicIP, index)
+		if r != nil {
+			r.mu.Lock()
+			node := r.node(publicIP)
+			node.Actions = append(node.Actions, newReportedAction(action, started, err))
+			r.mu.Unlock()
+		}
+		return err
+	}
+}
+
+func (r *deploymentReport) restarted(host string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.node(host).Restarts++
+}
+
+func (r *deploymentReport) rebooted(host string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.node(host).Reboots++
+}
+
+func (r *deploymentReport) retried(host string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.node(host).Retries++
+}
+
+// setDeploymentReport stores the report as deployment_report, with the versions read from the nodes by host.
+func setDeploymentReport(d *schema.ResourceData, r *deploymentReport, versions map[string]string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for host, version := range versions {
+		r.node(host).Version = version
+	}
+	report, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return d.Set("deployment_report", string(report))
+}
@@ -0,0 +1,75 @@
+package ravendb
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// environmentFilePath holds the environment variables of the ravendb service, read by the drop-in below.
+	environmentFilePath   = "/etc/ravendb/ravendb.env"
+	environmentDropInPath = "/etc/systemd/system/ravendb.service.d/environment.conf"
+)
+
+var environmentVariableName = regexp.MustCompile(`^RAVEN_[A-Za-z0-9_]+$`)
+
+func validateEnvironment(value interface{}, key string) ([]string, []error) {
+	var errs []error
+	for name, v := range value.(map[string]interface{}) {
+		if !environmentVariableName.MatchString(name) {
+			errs = append(errs, fmt.Errorf("%s: %s must start with RAVEN_ and only hold letters, digits and underscores", key, name))
+		}
+		if strings.ContainsAny(v.(string), "\r\n") {
+			errs = append(errs, fmt.Errorf("%s: the value of %s must be a single line", key, name))
+		}
+	}
+	return nil, errs
+}
+
+// environmentFile renders variables as a systemd environment file, in a stable order.
+func environmentFile(variables map[string]string) []byte {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var file strings.Builder
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	for _, name := range names {
+		file.WriteString(name + "=\"" + escaper.Replace(variables[name]) + "\"\n")
+	}
+	return []byte(file.String())
+}
+
+// writeEnvironment writes sc.Environment and the drop-in loading it into the ravendb service, or removes them
+// when there are no variables. Only set variables go into the fingerprint, so nodes configured before they
+// existed aren't restarted.
+func (sc *ServerConfig) writeEnvironment(publicIP string, conn Transport, stdoutBuf *nodeLog, fingerprint io.Writer) error {
+	if len(sc.Environment) == 0 {
+		return sc.execute(publicIP, []string{
+			"if [ -e " + environmentDropInPath + " ]; then sudo rm -f " + environmentDropInPath + " " + environmentFilePath + " && sudo systemctl daemon-reload; fi",
+		}, "", stdoutBuf, conn)
+	}
+
+	file := environmentFile(sc.Environment)
+	fingerprint.Write([]byte(environmentFilePath))
+	fingerprint.Write(file)
+	err := uploadWithPermissions(conn, stdoutBuf, environmentFilePath, file, "0600", "root", "root")
+	if err != nil {
+		return err
+	}
+	err = sc.execute(publicIP, []string{"sudo mkdir -p " + path.Dir(environmentDropInPath)}, "", stdoutBuf, conn)
+	if err != nil {
+		return err
+	}
+	err = uploadWithPermissions(conn, stdoutBuf, environmentDropInPath, []byte("[Service]\nEnvironmentFile="+environmentFilePath+"\n"), "0644", "root", "root")
+	if err != nil {
+		return err
+	}
+	return sc.execute(publicIP, []string{"sudo systemctl daemon-reload"}, "", stdoutBuf, conn)
+}
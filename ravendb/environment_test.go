@@ -0,0 +1,19 @@
+package ravendb
+
+import "testing"
+
+func TestEnvironmentFile(t *testing.T) {
+	file := environmentFile(map[string]string{
+		"RAVEN_Security_Certificate_Password": `pa"ss\word`,
+		"RAVEN_DataDir":                       "/data",
+	})
+	expected := "RAVEN_DataDir=\"/data\"\nRAVEN_Security_Certificate_Password=\"pa\\\"ss\\\\word\"\n"
+	if string(file) != expected {
+		t.Errorf("expected %q, got %q", expected, file)
+	}
+
+	_, errs := validateEnvironment(map[string]interface{}{"RAVEN_Setup_Mode": "None", "PATH": "/bin", "RAVEN_Logs_Mode": "a\nb"}, "environment")
+	if len(errs) != 2 {
+		t.Errorf("expected PATH and the multiline value to be rejected, got %v", errs)
+	}
+}
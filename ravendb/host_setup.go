@@ -0,0 +1,267 @@
+package ravendb
+
+import (
+	"errors"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	UNATTENDED_UPGRADES_DISABLED        string = "disabled"
+	UNATTENDED_UPGRADES_EXCLUDE_RAVENDB string = "exclude_ravendb"
+)
+
+const unattendedUpgradesConfigPath = "/etc/apt/apt.conf.d/99ravendb-unattended-upgrades"
+
+const (
+	chronyServersBegin = "# BEGIN ravendb servers"
+	chronyServersEnd   = "# END ravendb servers"
+)
+
+const (
+	peerHostsBegin = "# BEGIN ravendb peers"
+	peerHostsEnd   = "# END ravendb peers"
+)
+
+// PeerHosts maps the node hostnames to private ip addresses in /etc/hosts, for clusters without split-horizon
+// DNS. PrivateIps is discovered on the hosts when empty.
+type PeerHosts struct {
+	PrivateIps []string
+}
+
+// ClockSync keeps the clock of the hosts synchronized with chrony, as drifting clocks break the cluster and
+// the validation of certificates.
+type ClockSync struct {
+	Servers []string
+	MaxSkew time.Duration
+}
+
+func unattendedUpgradesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Description:  "disabled turns unattended-upgrades off on the hosts, exclude_ravendb keeps it from upgrading the ravendb package and rebooting. It is left alone when unset.",
+		ValidateFunc: validation.StringInSlice([]string{UNATTENDED_UPGRADES_DISABLED, UNATTENDED_UPGRADES_EXCLUDE_RAVENDB}, false),
+	}
+}
+
+func clockSyncSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Installs and configures chrony on the hosts, and waits for their clocks to be synchronized before the cluster is formed.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"servers": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					Description: "The NTP servers to synchronize with. The sources of the distribution are used when omitted.",
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+				"max_skew_ms": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      500,
+					Description:  "The largest clock offset a host may have for the deploy to go on.",
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+func parseClockSync(d *schema.ResourceData) *ClockSync {
+	for _, v := range d.Get("clock_sync").(*schema.Set).List() {
+		value := v.(map[string]interface{})
+		clockSync := &ClockSync{
+			MaxSkew: time.Duration(value["max_skew_ms"].(int)) * time.Millisecond,
+		}
+		for _, server := range value["servers"].([]interface{}) {
+			clockSync.Servers = append(clockSync.Servers, server.(string))
+		}
+		return clockSync
+	}
+	return nil
+}
+
+// steps installs chrony when it is missing, points it to the configured servers and waits, for up to 5
+// minutes, until the clock offset is below MaxSkew. The commands depend on the distribution of the host.
+func (cs *ClockSync) steps(sc *ServerConfig, publicIP string) []hostStep {
+	if cs == nil {
+		return nil
+	}
+	return []hostStep{
+		func(conn Transport, stdoutBuf *nodeLog) error {
+			family, err := detectOsFamily(conn)
+			if err != nil {
+				return err
+			}
+			return sc.execute(publicIP, cs.commands(family), "", stdoutBuf, conn)
+		},
+	}
+}
+
+// commands returns the clock sync commands for a distribution of the given family. The servers are kept in a
+// marked block of the main chrony configuration, as chrony 3 doesn't read a conf.d directory.
+func (cs *ClockSync) commands(family string) []string {
+	config := "/etc/chrony/chrony.conf"
+	service := "chrony"
+	install := "timeout 100 bash -c -- 'while ! sudo apt-get update -y; do sleep 1; done' && sudo apt-get install -y chrony"
+	if family == OS_FAMILY_RHEL {
+		config = "/etc/chrony.conf"
+		service = "chronyd"
+		install = "{ command -v dnf > /dev/null && sudo dnf install -y chrony; } || sudo yum install -y chrony"
+	}
+
+	servers := "sudo sed -i '/^" + chronyServersBegin + "$/,/^" + chronyServersEnd + "$/d' " + config
+	if len(cs.Servers) > 0 {
+		lines := []string{"'" + chronyServersBegin + "'"}
+		for _, server := range cs.Servers {
+			lines = append(lines, "'server "+server+" iburst'")
+		}
+		lines = append(lines, "'"+chronyServersEnd+"'")
+		servers += " && printf '%s\\n' " + strings.Join(lines, " ") + " | sudo tee -a " + config
+	}
+	maxCorrection := strconv.FormatFloat(cs.MaxSkew.Seconds(), 'f', -1, 64)
+	return []string{
+		"command -v chronyd > /dev/null || { " + install + "; }",
+		servers,
+		"sudo systemctl enable " + service + " && sudo systemctl restart " + service,
+		"chronyc waitsync 30 " + maxCorrection + " || { chronyc tracking; echo 'The clock offset is not below " + cs.MaxSkew.String() + "'; exit 1; }",
+	}
+}
+
+// hostnameSteps set the hostname of the host to the fully qualified name in the url of the node, and map it
+// to the loopback address in /etc/hosts, so the hostname matches the node tag and the certificate.
+func (sc *ServerConfig) hostnameSteps(publicIP string, index int) ([]hostStep, error) {
+	if !sc.ManageHostname {
+		return nil, nil
+	}
+	u, err := url.Parse(sc.Url.List[index])
+	if err != nil {
+		return nil, err
+	}
+	fqdn := u.Hostname()
+	if net.ParseIP(fqdn) != nil {
+		return nil, errors.New("cannot set the hostname of " + publicIP + " from the ip address " + fqdn)
+	}
+	short := strings.SplitN(fqdn, ".", 2)[0]
+	return []hostStep{
+		sc.command(publicIP, "sudo hostnamectl set-hostname "+fqdn),
+		sc.command(publicIP, "sudo sed -i '/^127\\.0\\.1\\.1[[:space:]]/d' /etc/hosts && echo '127.0.1.1 "+fqdn+" "+short+"' | sudo tee -a /etc/hosts"),
+	}, nil
+}
+
+// prepareHost returns the steps that set the operating system of a host up before RavenDB is installed.
+func (sc *ServerConfig) prepareHost(publicIP string, index int) ([]hostStep, error) {
+	steps, err := sc.hostnameSteps(publicIP, index)
+	if err != nil {
+		return nil, err
+	}
+	if sc.UnattendedUpgrades != "" {
+		steps = append(steps, sc.command(publicIP, "command -v apt-get > /dev/null || { echo 'unattended_upgrades is only supported on Debian based distributions'; exit 1; }"))
+	}
+	switch sc.UnattendedUpgrades {
+	case UNATTENDED_UPGRADES_DISABLED:
+		steps = append(steps,
+			sc.command(publicIP, "if systemctl list-unit-files unattended-upgrades.service | grep -q unattended-upgrades; then sudo systemctl disable --now unattended-upgrades; fi"),
+			sc.command(publicIP, "echo 'APT::Periodic::Unattended-Upgrade \"0\";' | sudo tee "+unattendedUpgradesConfigPath),
+		)
+	case UNATTENDED_UPGRADES_EXCLUDE_RAVENDB:
+		steps = append(steps,
+			sc.command(publicIP, "printf '%s\\n' 'Unattended-Upgrade::Package-Blacklist { \"ravendb\"; };' 'Unattended-Upgrade::Automatic-Reboot \"false\";' | sudo tee "+unattendedUpgradesConfigPath),
+		)
+	}
+	return append(steps, sc.ClockSync.steps(sc, publicIP)...), nil
+}
+
+func peerHostsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Writes the hostname of every node url, mapped to the private ip address of its host, to /etc/hosts on all the nodes.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"private_ips": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					Description: "The private ip address of every node, in the order of the urls. The first address reported by `hostname -I` on each host is used when omitted.",
+					Elem: &schema.Schema{
+						Type:         schema.TypeString,
+						ValidateFunc: validation.IsIPAddress,
+					},
+				},
+			},
+		},
+	}
+}
+
+func parsePeerHosts(d *schema.ResourceData) *PeerHosts {
+	for _, v := range d.Get("peer_hosts").(*schema.Set).List() {
+		peerHosts := &PeerHosts{}
+		if v == nil {
+			return peerHosts
+		}
+		for _, ip := range v.(map[string]interface{})["private_ips"].([]interface{}) {
+			peerHosts.PrivateIps = append(peerHosts.PrivateIps, ip.(string))
+		}
+		return peerHosts
+	}
+	return nil
+}
+
+// writePeerHosts replaces the block of peer entries in /etc/hosts on every host.
+func (sc *ServerConfig) writePeerHosts() error {
+	if sc.PeerHosts == nil {
+		return nil
+	}
+
+	ips := sc.PeerHosts.PrivateIps
+	if len(ips) == 0 {
+		ips = make([]string, len(sc.Hosts))
+		err := sc.forEachHost(true, func(publicIP string, index int) error {
+			return sc.onHost(publicIP, func(conn Transport, stdoutBuf *nodeLog) error {
+				output, err := runCommand(conn, "hostname -I")
+				if err != nil {
+					return err
+				}
+				fields := strings.Fields(string(output))
+				if len(fields) == 0 {
+					return errors.New("no ip address found on " + publicIP)
+				}
+				ips[index] = fields[0]
+				return nil
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if len(ips) != len(sc.Url.List) {
+		return errors.New("peer_hosts requires a private ip for each of the " + strconv.Itoa(len(sc.Url.List)) + " node urls")
+	}
+
+	lines := []string{"'" + peerHostsBegin + "'"}
+	for i, nodeUrl := range sc.Url.List {
+		u, err := url.Parse(nodeUrl)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, "'"+ips[i]+" "+u.Hostname()+"'")
+	}
+	lines = append(lines, "'"+peerHostsEnd+"'")
+	cmd := "sudo sed -i '/^" + peerHostsBegin + "$/,/^" + peerHostsEnd + "$/d' /etc/hosts && printf '%s\\n' " + strings.Join(lines, " ") + " | sudo tee -a /etc/hosts"
+
+	return sc.forEachHost(sc.Parallel.Configure, func(publicIP string, index int) error {
+		return sc.onHost(publicIP, sc.command(publicIP, cmd))
+	})
+}
@@ -0,0 +1,178 @@
+package ravendb
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	internal_operations "github.com/ravendb/terraform-provider-ravendb/operations"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+// importCertificateVariable holds the cluster certificate (pfx, base64) an import authenticates with against
+// the nodes of a secured cluster.
+const importCertificateVariable = "RAVENDB_CERTIFICATE"
+
+// parseImportId splits the id of an import into the expected topology id, which may be empty, and the node
+// urls, e.g. 7a0c0c8b-37b6-4a73-8b6e-ddc7c0a5c9b8@https://a.example.com,https://b.example.com.
+func parseImportId(id string) (string, []string, error) {
+	var topologyId string
+	if at := strings.Index(id, "@"); at >= 0 && !strings.Contains(id[:at], "://") {
+		topologyId, id = id[:at], id[at+1:]
+	}
+	var urls []string
+	for _, u := range strings.Split(id, ",") {
+		u = strings.TrimSpace(u)
+		parsed, err := url.Parse(u)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return "", nil, errors.New("expected the import id to be [<topology id>@]<node url>[,<node url>...], got " + u + " as a node url")
+		}
+		urls = append(urls, strings.TrimSuffix(u, "/"))
+	}
+	return topologyId, urls, nil
+}
+
+// importCluster reads the topology of the cluster an import id points at. A single url stands for the whole
+// cluster, the urls of all its nodes are then taken from its topology, sorted by tag.
+func importCluster(id string) (ServerConfig, internal_operations.ClusterState, error) {
+	var state internal_operations.OperationGetClusterState
+	topologyId, urls, err := parseImportId(id)
+	if err != nil {
+		return ServerConfig{}, state.Result, err
+	}
+
+	sc := ServerConfig{Unsecured: true}
+	sc.Url.List = urls
+	for _, u := range urls {
+		if strings.HasPrefix(u, "https://") {
+			sc.Unsecured = false
+		}
+	}
+	if !sc.Unsecured {
+		certificate, ok := os.LookupEnv(importCertificateVariable)
+		if !ok {
+			return sc, state.Result, errors.New(importCertificateVariable + " must hold the cluster certificate to import a secured cluster")
+		}
+		sc.ClusterCertificate, err = base64.StdEncoding.DecodeString(certificate)
+		if err != nil {
+			return sc, state.Result, errors.New(importCertificateVariable + " is not base64: " + err.Error())
+		}
+	}
+
+	store, err := getStore(&sc, 0)
+	if err != nil {
+		return sc, state.Result, err
+	}
+	defer store.Close()
+	err = executeWithRetries(store, &state)
+	if err != nil {
+		return sc, state.Result, err
+	}
+	if topologyId != "" && state.Result.Topology.TopologyId != topologyId {
+		return sc, state.Result, errors.New(urls[0] + " belongs to cluster " + state.Result.Topology.TopologyId + ", not to " + topologyId)
+	}
+
+	if len(urls) == 1 {
+		nodes := map[string]string{}
+		for _, role := range []map[string]string{state.Result.Topology.Members, state.Result.Topology.Promotables, state.Result.Topology.Watchers} {
+			for tag, u := range role {
+				nodes[tag] = u
+			}
+		}
+		tags := make([]string, 0, len(nodes))
+		for tag := range nodes {
+			tags = append(tags, tag)
+		}
+		sort.Strings(tags)
+		sc.Url.List = make([]string, len(tags))
+		for i, tag := range tags {
+			sc.Url.List[i] = nodes[tag]
+		}
+	}
+	return sc, state.Result, nil
+}
+
+// resourceServerImport adopts a running cluster, see parseImportId for the id. Only what can be read over HTTP
+// is imported: the node urls, their hostnames as the hosts and the certificate. The ssh access, package, license
+// and settings come from the configuration on the next apply.
+func resourceServerImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	sc, state, err := importCluster(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := make([]string, len(sc.Url.List))
+	for i, nodeUrl := range sc.Url.List {
+		u, err := url.Parse(nodeUrl)
+		if err != nil {
+			return nil, err
+		}
+		hosts[i] = u.Hostname()
+	}
+
+	d.SetId(state.Topology.TopologyId)
+	err = d.Set("hosts", hosts)
+	if err != nil {
+		return nil, err
+	}
+	err = d.Set("url", []interface{}{map[string]interface{}{"list": sc.Url.List}})
+	if err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, setImportedCertificate(d, sc)
+}
+
+// resourceClusterImport adopts a running cluster as a ravendb_cluster, see parseImportId for the id.
+func resourceClusterImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	sc, state, err := importCluster(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(state.Topology.TopologyId)
+	err = d.Set("nodes", sc.Url.List)
+	if err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, setImportedCertificate(d, sc)
+}
+
+func setImportedCertificate(d *schema.ResourceData, sc ServerConfig) error {
+	err := d.Set("unsecured", sc.Unsecured)
+	if err != nil || sc.ClusterCertificate == nil {
+		return err
+	}
+	return d.Set("certificate", base64.StdEncoding.EncodeToString(sc.ClusterCertificate))
+}
+
+// isImportedServer reports whether the state of a ravendb_server was imported and not applied since, so it has
+// no ssh access to read the nodes with yet.
+func isImportedServer(d *schema.ResourceData) bool {
+	return d.Get("connection").(string) != CONNECTION_LOCAL && d.Get("ssh").(*schema.Set).Len() == 0
+}
+
+// readImportedServerState refreshes what can be read of an imported ravendb_server over HTTP.
+func readImportedServerState(d *schema.ResourceData, stores *storeCache) error {
+	sc := ServerConfig{stores: stores}
+	var err error
+	sc.Hosts, sc.Url.List, err = nodeLists(d.Get)
+	if err != nil {
+		return err
+	}
+	sc.Unsecured = d.Get("unsecured").(bool)
+	cert, err := base64.StdEncoding.DecodeString(d.Get("certificate").(string))
+	if err != nil {
+		return err
+	}
+	if allZero(cert) == false {
+		sc.ClusterCertificate = cert
+	}
+
+	err = d.Set("dns_records", dnsRecords(sc.Hosts, sc.Url.List))
+	if err != nil {
+		return err
+	}
+	return sc.setClusterTopology(d)
+}
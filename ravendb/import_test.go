@@ -0,0 +1,30 @@
+package ravendb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseImportId(t *testing.T) {
+	topologyId, urls, err := parseImportId("7a0c0c8b-37b6-4a73-8b6e-ddc7c0a5c9b8@https://a.example.com, https://b.example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if topologyId != "7a0c0c8b-37b6-4a73-8b6e-ddc7c0a5c9b8" {
+		t.Errorf("unexpected topology id %s", topologyId)
+	}
+	if !reflect.DeepEqual(urls, []string{"https://a.example.com", "https://b.example.com"}) {
+		t.Errorf("unexpected urls %v", urls)
+	}
+
+	topologyId, urls, err = parseImportId("https://admin@a.example.com:8080")
+	if err != nil || topologyId != "" || !reflect.DeepEqual(urls, []string{"https://admin@a.example.com:8080"}) {
+		t.Errorf("expected a single url without topology id, got %s %v %v", topologyId, urls, err)
+	}
+
+	for _, id := range []string{"7a0c0c8b-37b6-4a73-8b6e-ddc7c0a5c9b8", "a.example.com", "https://a.example.com,"} {
+		if _, _, err := parseImportId(id); err == nil {
+			t.Errorf("expected %q to be rejected", id)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package ravendb
+
+import (
+	"encoding/json"
+	"errors"
+	internal_operations "github.com/ravendb/terraform-provider-ravendb/operations"
+	"strings"
+	"time"
+)
+
+// NODE_HEALTH_TIMEOUT is how long a restarted node has to load its cluster state before it is reported unhealthy.
+const NODE_HEALTH_TIMEOUT time.Duration = 2 * time.Minute
+
+// checkNodeHealth verifies the node at index after it was restarted, on its own rather than through the cluster:
+// it runs the build of the package and loaded its cluster topology, in which it has its own url. The alive endpoint
+// was already polled by configureNode. Errors name the node, so a failed deploy points at it.
+func (sc *ServerConfig) checkNodeHealth(publicIP string, index int, httpUrl string, conn Transport, stdoutBuf *nodeLog) error {
+	unhealthy := func(reason string) error {
+		return errors.New("node " + publicIP + " (" + httpUrl + ") is unhealthy after the restart: " + reason)
+	}
+
+	if sc.Package.Version != "" {
+		output, err := runCommand(conn, "curl -sf "+httpUrl+"/build/version")
+		if err != nil {
+			return unhealthy("unable to read its build: " + err.Error() + ": " + string(output))
+		}
+		var build struct {
+			FullVersion string `json:"FullVersion"`
+		}
+		err = json.Unmarshal(output, &build)
+		if err != nil {
+			return unhealthy("unable to read its build: " + err.Error())
+		}
+		if build.FullVersion != sc.Package.Version && strings.HasPrefix(build.FullVersion, sc.Package.Version+"-") == false {
+			return unhealthy("it runs build " + build.FullVersion + " instead of " + sc.Package.Version)
+		}
+		stdoutBuf.WriteString("RavenDB " + build.FullVersion + " is running\n")
+	}
+
+	store, err := getStore(sc, index)
+	if err != nil {
+		return unhealthy(err.Error())
+	}
+	err = waitFor("the cluster topology of "+httpUrl, NODE_HEALTH_TIMEOUT, func() (bool, error) {
+		state := internal_operations.OperationGetClusterState{}
+		err := executeWithRetries(store, &state)
+		if err != nil {
+			return false, err
+		}
+		// the request may have been served by another node of the cluster
+		topology := state.Result.Topology
+		for _, nodes := range []map[string]string{topology.Members, topology.Promotables, topology.Watchers} {
+			if nodeUrl, ok := nodes[state.Result.NodeTag]; ok {
+				return nodeUrl == httpUrl, nil
+			}
+		}
+		return false, errors.New("node " + state.Result.NodeTag + " is not part of its own topology")
+	})
+	if err != nil {
+		return unhealthy(err.Error())
+	}
+	stdoutBuf.WriteString("The node loaded its cluster topology\n")
+	return nil
+}
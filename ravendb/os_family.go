@@ -0,0 +1,156 @@
+package ravendb
+
+import (
+	"bufio"
+	"errors"
+	"strings"
+)
+
+const (
+	OS_FAMILY_DEBIAN string = "debian"
+	OS_FAMILY_RHEL   string = "rhel"
+)
+
+// osFamilyIds maps the ids of /etc/os-release, ID and ID_LIKE, to the family whose install path they use.
+var osFamilyIds = map[string]string{
+	"debian":    OS_FAMILY_DEBIAN,
+	"ubuntu":    OS_FAMILY_DEBIAN,
+	"rhel":      OS_FAMILY_RHEL,
+	"fedora":    OS_FAMILY_RHEL,
+	"centos":    OS_FAMILY_RHEL,
+	"rocky":     OS_FAMILY_RHEL,
+	"almalinux": OS_FAMILY_RHEL,
+	"ol":        OS_FAMILY_RHEL,
+	"amzn":      OS_FAMILY_RHEL,
+}
+
+// tarballArchitectures maps the package suffixes of packageArchitectures to the architecture of the linux tarball
+// installed on distributions without Debian packages.
+var tarballArchitectures = map[string]string{
+	"-0_amd64.deb": "linux-x64",
+	"-0_armhf.deb": "raspberry-pi",
+	"_linux-arm64": "linux-arm64",
+}
+
+const ravendbServiceUnit = `[Unit]
+Description=RavenDB
+After=network.target
+
+[Service]
+User=ravendb
+LimitCORE=infinity
+LimitNOFILE=65535
+LimitRSS=infinity
+LimitAS=infinity
+Restart=on-failure
+TimeoutStopSec=300
+ExecStart=/usr/lib/ravendb/server/Raven.Server -c /etc/ravendb/settings.json
+
+[Install]
+WantedBy=multi-user.target
+`
+
+const ravendbDefaultSettings = `{"DataDir": "/var/lib/ravendb/data", "Logs.Path": "/var/log/ravendb/logs"}`
+
+// osFamily returns the family of the distribution described by osRelease, the contents of /etc/os-release.
+func osFamily(osRelease string) (string, error) {
+	fields := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(osRelease))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), "=", 2)
+		if len(parts) == 2 {
+			fields[parts[0]] = strings.Trim(parts[1], `"'`)
+		}
+	}
+	for _, id := range append([]string{fields["ID"]}, strings.Fields(fields["ID_LIKE"])...) {
+		if family, ok := osFamilyIds[id]; ok {
+			return family, nil
+		}
+	}
+	name := fields["PRETTY_NAME"]
+	if name == "" {
+		name = fields["ID"]
+	}
+	return "", errors.New("unsupported distribution " + name + ": RavenDB can be installed on Debian and RHEL based distributions")
+}
+
+func detectOsFamily(conn Transport) (string, error) {
+	osRelease, err := runCommand(conn, "cat /etc/os-release")
+	if err != nil {
+		return "", errors.New("unable to read /etc/os-release: " + err.Error() + ": " + string(osRelease))
+	}
+	return osFamily(string(osRelease))
+}
+
+// installedVersion returns the version of RavenDB installed on a host of the given family, or an empty string when it
+// isn't installed or its service isn't running.
+func installedVersion(conn Transport, family string) string {
+	if _, err := runCommand(conn, "systemctl is-active --quiet ravendb"); err != nil {
+		return ""
+	}
+	cmd := "sudo /usr/lib/ravendb/server/Raven.Server --version"
+	if family == OS_FAMILY_DEBIAN {
+		cmd = "dpkg-query -W -f='${Version}' ravendb"
+	}
+	output, err := runCommand(conn, cmd)
+	if err != nil {
+		return ""
+	}
+	// the Debian package carries a revision, e.g. 5.4.107-0
+	return strings.SplitN(strings.TrimSpace(string(output)), "-", 2)[0]
+}
+
+// installCommands returns the commands installing package p on a distribution of the given family. Debian
+// based distributions install the Debian package, the others the linux tarball laid out the same way, with a
+// systemd unit of their own. A pushed package is already on the host, it isn't downloaded.
+func (sc *ServerConfig) installCommands(family string, p Package) []string {
+	var commands []string
+	if p.Distribution != PACKAGE_DISTRIBUTION_PUSH {
+		file, link := p.packageFile(family)
+		commands = append(commands, "wget -nv -O "+file+" "+link)
+	}
+	if family == OS_FAMILY_DEBIAN {
+		return append(commands,
+			"timeout 100 bash -c -- 'while ! sudo apt-get update -y; do sleep 1; done'",
+			"sudo apt-get install -y -f ./ravendb.deb",
+		)
+	}
+	return append(commands,
+		"{ command -v dnf > /dev/null && sudo dnf install -y bzip2 libicu; } || sudo yum install -y bzip2 libicu",
+		"id ravendb > /dev/null 2>&1 || sudo useradd --system --home-dir /var/lib/ravendb --shell /sbin/nologin ravendb",
+		"sudo rm -rf /usr/lib/ravendb/server && sudo mkdir -p /usr/lib/ravendb/server /etc/ravendb /var/lib/ravendb/data /var/log/ravendb",
+		"sudo tar -xjf ravendb.tar.bz2 -C /usr/lib/ravendb/server --strip-components=2 RavenDB/Server",
+		"test -f /etc/ravendb/settings.json || echo '"+ravendbDefaultSettings+"' | sudo tee /etc/ravendb/settings.json",
+		"printf '%s' '"+ravendbServiceUnit+"' | sudo tee /etc/systemd/system/ravendb.service",
+		"sudo chown -R ravendb:ravendb /usr/lib/ravendb /etc/ravendb /var/lib/ravendb /var/log/ravendb",
+		"sudo systemctl daemon-reload && sudo systemctl enable ravendb",
+	)
+}
+
+// purgeCommands returns the commands removing RavenDB from a distribution of the given family.
+func purgeCommands(family string) []string {
+	if family == OS_FAMILY_DEBIAN {
+		return []string{"sudo apt-get -y purge ravendb"}
+	}
+	return []string{
+		"sudo systemctl disable --now ravendb || true",
+		"sudo rm -rf /usr/lib/ravendb /etc/ravendb /etc/systemd/system/ravendb.service",
+		"sudo systemctl daemon-reload",
+	}
+}
+
+// cleanupCommands returns the commands removing what a failed install left behind on a distribution of the given
+// family: the downloaded package and an interrupted package manager transaction.
+func cleanupCommands(family string) []string {
+	if family == OS_FAMILY_DEBIAN {
+		return []string{
+			"rm -f ravendb.deb",
+			"sudo dpkg --configure -a",
+			"sudo apt-get clean",
+		}
+	}
+	return []string{
+		"rm -f ravendb.tar.bz2",
+		"{ command -v dnf > /dev/null && sudo dnf clean all; } || sudo yum clean all",
+	}
+}
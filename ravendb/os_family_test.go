@@ -0,0 +1,71 @@
+package ravendb
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOsFamily(t *testing.T) {
+	releases := map[string]string{
+		"ID=ubuntu\nID_LIKE=debian\n":                    OS_FAMILY_DEBIAN,
+		"ID=debian\n":                                    OS_FAMILY_DEBIAN,
+		"ID=\"rocky\"\nID_LIKE=\"rhel centos fedora\"\n": OS_FAMILY_RHEL,
+		"ID=\"amzn\"\nID_LIKE=\"centos rhel fedora\"\n":  OS_FAMILY_RHEL,
+		"ID=linuxmint\nID_LIKE=\"ubuntu debian\"\n":      OS_FAMILY_DEBIAN,
+	}
+	for release, expected := range releases {
+		family, err := osFamily(release)
+		if err != nil || family != expected {
+			t.Errorf("%q: expected %s, got %s (%v)", release, expected, family, err)
+		}
+	}
+	_, err := osFamily("ID=alpine\nPRETTY_NAME=\"Alpine Linux v3.18\"\n")
+	if err == nil {
+		t.Error("expected alpine to be unsupported")
+	}
+}
+
+func TestInstallCommandsUseNodeArch(t *testing.T) {
+	sc := ServerConfig{
+		Package:   Package{Version: "6.0.105", Arch: packageArch("amd64")},
+		NodeArchs: []string{"", packageArch("arm64")},
+	}
+	expected := []string{"linux-x64", "linux-arm64", "linux-x64"}
+	for index, arch := range expected {
+		url := sc.installCommands(OS_FAMILY_RHEL, sc.nodePackage(index))[0]
+		if !strings.HasSuffix(url, "/RavenDB-6.0.105-"+arch+".tar.bz2") {
+			t.Errorf("node %d: expected the %s tarball, got %s", index, arch, url)
+		}
+	}
+}
+
+func TestInstallCommandsDontDownloadPushedPackage(t *testing.T) {
+	sc := ServerConfig{Package: Package{Version: "6.0.105", Arch: packageArch("amd64"), Distribution: PACKAGE_DISTRIBUTION_PUSH}}
+	for _, family := range []string{OS_FAMILY_DEBIAN, OS_FAMILY_RHEL} {
+		for _, cmd := range sc.installCommands(family, sc.Package) {
+			if strings.HasPrefix(cmd, "wget") {
+				t.Errorf("%s: expected the pushed package not to be downloaded, got %s", family, cmd)
+			}
+		}
+	}
+}
+
+func TestClockSyncCommands(t *testing.T) {
+	cs := &ClockSync{Servers: []string{"time.example.com"}, MaxSkew: 100 * time.Millisecond}
+	expected := map[string][]string{
+		OS_FAMILY_DEBIAN: {"apt-get install -y chrony", "/etc/chrony/chrony.conf", "systemctl restart chrony"},
+		OS_FAMILY_RHEL:   {"dnf install -y chrony", "/etc/chrony.conf", "systemctl restart chronyd"},
+	}
+	for family, parts := range expected {
+		commands := strings.Join(cs.commands(family), "\n")
+		for _, part := range parts {
+			if !strings.Contains(commands, part) {
+				t.Errorf("%s: expected %q in\n%s", family, part, commands)
+			}
+		}
+		if strings.Contains(commands, "conf.d") {
+			t.Errorf("%s: expected the servers in the main configuration, got\n%s", family, commands)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package ravendb
+
+import (
+	"encoding/xml"
+	"errors"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const PACKAGE_CHANNEL_DAILY string = "daily"
+
+const (
+	PACKAGE_DISTRIBUTION_PULL string = "pull"
+	PACKAGE_DISTRIBUTION_PUSH string = "push"
+)
+
+// packageChannels maps every release channel to the feed its packages are downloaded from. The packages of a
+// feed are all named the same way, only the feed differs. A channel is only added with a feed that can be listed,
+// as version constraints are resolved from the listing.
+var packageChannels = map[string]string{
+	PACKAGE_CHANNEL_DAILY: "https://daily-builds.s3.us-east-1.amazonaws.com",
+}
+
+// feed returns the url packages of the channel are downloaded from. The daily builds are used when no channel is set.
+func (p Package) feed() string {
+	if feed, ok := packageChannels[p.Channel]; ok {
+		return feed
+	}
+	return packageChannels[PACKAGE_CHANNEL_DAILY]
+}
+
+// debianUrl returns the url of the Debian package of the version.
+func (p Package) debianUrl() string {
+	return p.feed() + "/ravendb_" + p.Version + p.Arch
+}
+
+// tarballUrl returns the url of the linux tarball of the version, for distributions without Debian packages.
+func (p Package) tarballUrl() string {
+	return p.feed() + "/RavenDB-" + p.Version + "-" + tarballArchitectures[p.Arch] + ".tar.bz2"
+}
+
+// packageFile returns the file the package is installed from on a distribution of the given family, and the url
+// it is downloaded from.
+func (p Package) packageFile(family string) (string, string) {
+	if family == OS_FAMILY_DEBIAN {
+		return "ravendb.deb", p.debianUrl()
+	}
+	return "ravendb.tar.bz2", p.tarballUrl()
+}
+
+// isVersionConstraint reports whether version is a constraint such as "~> 5.4" or ">= 6.0.2" rather than a
+// concrete version.
+func isVersionConstraint(v string) bool {
+	return strings.ContainsAny(v, "<>=~!,")
+}
+
+// packageArch returns the package suffix of arch, one of the keys of packageArchitectures. amd64 is used when
+// arch is empty, and unknown values are taken as a suffix as is.
+func packageArch(arch string) string {
+	if suffix, ok := packageArchitectures[strings.ToLower(arch)]; ok {
+		return suffix
+	}
+	if len(strings.TrimSpace(arch)) == 0 {
+		return packageArchitectures["amd64"]
+	}
+	return arch
+}
+
+type s3Listing struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// availableVersions lists the versions of the Debian packages of the feed of p, for the architecture of p.
+func (p Package) availableVersions() ([]*version.Version, error) {
+	feed, err := url.Parse(p.feed())
+	if err != nil {
+		return nil, err
+	}
+	prefix := strings.TrimPrefix(feed.Path+"/", "/") + "ravendb_"
+
+	var versions []*version.Version
+	query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+	for {
+		response, err := http.Get(feed.Scheme + "://" + feed.Host + "/?" + query.Encode())
+		if err != nil {
+			return nil, err
+		}
+		var listing s3Listing
+		if response.StatusCode != http.StatusOK {
+			response.Body.Close()
+			return nil, errors.New("unable to list the packages of " + p.feed() + ". HTTP status code: " + strconv.Itoa(response.StatusCode))
+		}
+		err = xml.NewDecoder(response.Body).Decode(&listing)
+		response.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, content := range listing.Contents {
+			if !strings.HasSuffix(content.Key, p.Arch) {
+				continue
+			}
+			v, err := version.NewVersion(strings.TrimSuffix(strings.TrimPrefix(content.Key, prefix), p.Arch))
+			if err == nil {
+				versions = append(versions, v)
+			}
+		}
+		if !listing.IsTruncated {
+			return versions, nil
+		}
+		query.Set("continuation-token", listing.NextContinuationToken)
+	}
+}
+
+// resolveVersion returns the newest version of the feed of p that satisfies constraint.
+func (p Package) resolveVersion(constraint string) (string, error) {
+	constraints, err := version.NewConstraint(constraint)
+	if err != nil {
+		return "", err
+	}
+	versions, err := p.availableVersions()
+	if err != nil {
+		return "", err
+	}
+	var resolved *version.Version
+	for _, v := range versions {
+		if constraints.Check(v) && (resolved == nil || v.GreaterThan(resolved)) {
+			resolved = v
+		}
+	}
+	if resolved == nil {
+		return "", errors.New("no package of " + p.feed() + " satisfies the version constraint " + constraint)
+	}
+	return resolved.Original(), nil
+}
+
+// customizePackageVersion resolves a version constraint of the package to the newest matching version at plan
+// time and keeps it in resolved_version. The version is kept as long as it satisfies the constraint and the
+// package block doesn't change, so plans don't move to a newer build by themselves. With skip_url_check the plan
+// doesn't reach the feed, the version is resolved on apply instead.
+func customizePackageVersion(d *schema.ResourceDiff) error {
+	if d.NewValueKnown("package") == false {
+		return nil
+	}
+	list := d.Get("package").(*schema.Set).List()
+	resolved := d.Get("resolved_version").(string)
+	if len(list) == 0 {
+		return nil
+	}
+	value := list[0].(map[string]interface{})
+	v := value["version"].(string)
+	if !isVersionConstraint(v) {
+		if resolved != "" && resolved != v {
+			return d.SetNew("resolved_version", v)
+		}
+		return nil
+	}
+
+	constraints, err := version.NewConstraint(v)
+	if err != nil {
+		return errors.New("invalid package version constraint " + v + ": " + err.Error())
+	}
+	if current, err := version.NewVersion(resolved); err == nil && constraints.Check(current) && !d.HasChange("package") {
+		return nil
+	}
+	if value["skip_url_check"].(bool) {
+		return d.SetNewComputed("resolved_version")
+	}
+	p := Package{
+		Arch:    packageArch(value["arch"].(string)),
+		Channel: value["channel"].(string),
+	}
+	resolved, err = p.resolveVersion(v)
+	if err != nil {
+		return err
+	}
+	return d.SetNew("resolved_version", resolved)
+}
+
+// resolvedPackageVersion returns the version the constraint of p was resolved to. A plan with skip_url_check
+// leaves it unknown, it is then resolved now, on apply, and kept in resolved_version.
+func resolvedPackageVersion(d *schema.ResourceData, p Package) (string, error) {
+	resolved := d.Get("resolved_version").(string)
+	if resolved != "" || !p.SkipUrlCheck {
+		return resolved, nil
+	}
+	p.Arch = packageArch(p.Arch)
+	resolved, err := p.resolveVersion(p.Version)
+	if err != nil {
+		return "", err
+	}
+	return resolved, d.Set("resolved_version", resolved)
+}
+
+// packageCache holds the packages downloaded by the machine running terraform, by url, so each package is
+// downloaded once however many hosts it is pushed to. The packages are kept in files, in the cache_dir of the
+// package when it is set, or else in a temporary directory removed by close.
+type packageCache struct {
+	mu       sync.Mutex
+	packages map[string]*cachedPackage
+	tempDir  string
+}
+
+// cachedPackage is the file a package was downloaded to. Its lock is held while the package is downloaded, so
+// the hosts pushing the same package wait for a single download, while other packages download at once.
+type cachedPackage struct {
+	mu   sync.Mutex
+	file string
+}
+
+// get returns the file of the package at link, downloading it unless it is already in the cache or in cacheDir.
+// Downloads are kept in cacheDir when it is set, for the next applies.
+func (c *packageCache) get(link string, cacheDir string) (string, error) {
+	c.mu.Lock()
+	if c.packages == nil {
+		c.packages = map[string]*cachedPackage{}
+	}
+	cached, ok := c.packages[link]
+	if !ok {
+		cached = &cachedPackage{}
+		c.packages[link] = cached
+	}
+	c.mu.Unlock()
+
+	cached.mu.Lock()
+	defer cached.mu.Unlock()
+	if cached.file != "" {
+		return cached.file, nil
+	}
+	dir := cacheDir
+	if dir == "" {
+		var err error
+		dir, err = c.temporaryDir()
+		if err != nil {
+			return "", err
+		}
+	}
+	file, err := downloadPackage(link, dir)
+	if err != nil {
+		return "", err
+	}
+	cached.file = file
+	return file, nil
+}
+
+func (c *packageCache) temporaryDir() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tempDir != "" {
+		return c.tempDir, nil
+	}
+	dir, err := ioutil.TempDir("", "ravendb-packages")
+	if err != nil {
+		return "", err
+	}
+	c.tempDir = dir
+	return dir, nil
+}
+
+// close removes the packages downloaded to the temporary directory, once they were pushed to all the hosts.
+func (c *packageCache) close() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tempDir == "" {
+		return
+	}
+	os.RemoveAll(c.tempDir)
+	c.tempDir = ""
+	c.packages = nil
+}
+
+// downloadPackage streams the package at link to a file in dir, unless dir already has it, and returns the file.
+func downloadPackage(link string, dir string) (string, error) {
+	cached := filepath.Join(dir, path.Base(link))
+	if _, err := os.Stat(cached); err == nil {
+		return cached, nil
+	}
+
+	response, err := http.Get(link)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", errors.New("unable to download the package " + link + ". HTTP status code: " + strconv.Itoa(response.StatusCode))
+	}
+
+	// written next to the cached file and renamed, so an interrupted download never leaves a truncated package
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return "", err
+	}
+	partial, err := ioutil.TempFile(dir, path.Base(link)+".*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(partial.Name())
+	_, err = io.Copy(partial, response.Body)
+	if closeErr := partial.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+	return cached, os.Rename(partial.Name(), cached)
+}
+
+// pushPackage uploads the package of the host of conn, downloaded by the machine running terraform, so the host
+// doesn't download it itself.
+func (sc *ServerConfig) pushPackage(conn Transport, stdoutBuf *nodeLog, family string, p Package) error {
+	file, link := p.packageFile(family)
+	local, err := sc.packages.get(link, p.CacheDir)
+	if err != nil {
+		return err
+	}
+	return uploadFileWithPermissions(conn, stdoutBuf, file, local, "0644", "root", "root")
+}
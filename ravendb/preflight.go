@@ -0,0 +1,134 @@
+package ravendb
+
+import (
+	"errors"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"strconv"
+	"strings"
+)
+
+// Preflight holds the requirements checked on every host before anything is installed on it. Zero values are
+// not checked.
+type Preflight struct {
+	MinMemoryMb   int
+	MinFreeDiskMb int
+	DataPath      string
+	CheckPorts    bool
+}
+
+func preflightSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Checks run on every host before anything is installed. The deploy fails with all the unmet requirements of all the hosts. systemd is always required.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"min_memory_mb": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"min_free_disk_mb": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Description:  "The free space required on the file system of data_path.",
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"data_path": {
+					Type:     schema.TypeString,
+					Optional: true,
+					Default:  "/var/lib/ravendb",
+				},
+				"check_ports": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Description: "Fails when the http or tcp port of the node is bound by another process than RavenDB.",
+				},
+			},
+		},
+	}
+}
+
+func parsePreflight(d *schema.ResourceData) *Preflight {
+	for _, v := range d.Get("preflight").(*schema.Set).List() {
+		value := v.(map[string]interface{})
+		return &Preflight{
+			MinMemoryMb:   value["min_memory_mb"].(int),
+			MinFreeDiskMb: value["min_free_disk_mb"].(int),
+			DataPath:      value["data_path"].(string),
+			CheckPorts:    value["check_ports"].(bool),
+		}
+	}
+	return nil
+}
+
+// runPreflight checks the requirements of sc.Preflight on all the hosts at once.
+func (sc *ServerConfig) runPreflight() error {
+	if sc.Preflight == nil {
+		return nil
+	}
+	return sc.forEachHost(true, func(publicIP string, index int) error {
+		return sc.onHost(publicIP, func(conn Transport, stdoutBuf *nodeLog) error {
+			failures := sc.Preflight.check(conn, []int{sc.Url.HttpPort, sc.Url.TcpPort})
+			if len(failures) == 0 {
+				return nil
+			}
+			var result error
+			for _, failure := range failures {
+				stdoutBuf.WriteString("Pre-flight check failed: " + failure + "\n")
+				result = multierror.Append(result, errors.New(publicIP+": "+failure))
+			}
+			return result
+		})
+	})
+}
+
+// check returns the requirements the host of conn doesn't meet.
+func (p *Preflight) check(conn Transport, ports []int) []string {
+	var failures []string
+	if _, err := runCommand(conn, "test -d /run/systemd/system"); err != nil {
+		failures = append(failures, "systemd is not running")
+	}
+
+	if p.MinMemoryMb > 0 {
+		memory, err := commandInt(conn, "awk '/^MemTotal:/ { print int($2 / 1024) }' /proc/meminfo")
+		if err != nil {
+			failures = append(failures, "unable to read the memory size: "+err.Error())
+		} else if memory < p.MinMemoryMb {
+			failures = append(failures, strconv.Itoa(memory)+" MB of memory, "+strconv.Itoa(p.MinMemoryMb)+" MB required")
+		}
+	}
+
+	if p.MinFreeDiskMb > 0 {
+		// the data path doesn't exist before RavenDB is installed, its closest existing parent is on the same file system
+		free, err := commandInt(conn, "p='"+p.DataPath+"'; while [ ! -e \"$p\" ]; do p=$(dirname \"$p\"); done; df -Pm \"$p\" | awk 'NR == 2 { print $4 }'")
+		if err != nil {
+			failures = append(failures, "unable to read the free disk space of "+p.DataPath+": "+err.Error())
+		} else if free < p.MinFreeDiskMb {
+			failures = append(failures, strconv.Itoa(free)+" MB free on "+p.DataPath+", "+strconv.Itoa(p.MinFreeDiskMb)+" MB required")
+		}
+	}
+
+	if p.CheckPorts {
+		for _, port := range ports {
+			output, err := runCommand(conn, "sudo ss -Hltnp 'sport = :"+strconv.Itoa(port)+"'")
+			if err != nil {
+				failures = append(failures, "unable to check port "+strconv.Itoa(port)+": "+err.Error())
+			} else if listeners := strings.TrimSpace(string(output)); listeners != "" && !strings.Contains(listeners, "Raven.Server") {
+				failures = append(failures, "port "+strconv.Itoa(port)+" is already bound: "+listeners)
+			}
+		}
+	}
+	return failures
+}
+
+func commandInt(conn Transport, cmd string) (int, error) {
+	output, err := runCommand(conn, cmd)
+	if err != nil {
+		return 0, errors.New(err.Error() + ": " + string(output))
+	}
+	return strconv.Atoi(strings.TrimSpace(string(output)))
+}
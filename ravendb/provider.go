@@ -1,6 +1,8 @@
 package ravendb
 
 import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -9,7 +11,45 @@ func Provider() *schema.Provider {
 	return &schema.Provider{
 		Schema: map[string]*schema.Schema{},
 		ResourcesMap: map[string]*schema.Resource{
-			"ravendb_server": resourceRavendbServer(),
+			"ravendb_server":                  resourceRavendbServer(),
+			"ravendb_node":                    resourceRavendbNode(),
+			"ravendb_cluster":                 resourceRavendbCluster(),
+			"ravendb_studio_configuration":    resourceRavendbStudioConfiguration(),
+			"ravendb_migration":               resourceRavendbMigration(),
+			"ravendb_smuggler":                resourceRavendbSmuggler(),
+			"ravendb_backup_task":             resourceRavendbBackupTask(),
+			"ravendb_backup":                  resourceRavendbBackup(),
+			"ravendb_server_wide_replication": resourceRavendbServerWideReplication(),
+			"ravendb_database_settings":       resourceRavendbDatabaseSettings(),
+			"ravendb_admin_operation":         resourceRavendbAdminOperation(),
+			"ravendb_queue_sink":              resourceRavendbQueueSink(),
 		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"ravendb_admin_logs":     dataSourceRavendbAdminLogs(),
+			"ravendb_aws_hosts":      dataSourceRavendbAwsHosts(),
+			"ravendb_azure_hosts":    dataSourceRavendbAzureHosts(),
+			"ravendb_cloud_init":     dataSourceRavendbCloudInit(),
+			"ravendb_cluster_health": dataSourceRavendbClusterHealth(),
+			"ravendb_databases":      dataSourceRavendbDatabases(),
+			"ravendb_gcp_hosts":      dataSourceRavendbGcpHosts(),
+			"ravendb_index_errors":   dataSourceRavendbIndexErrors(),
+			"ravendb_indexes":        dataSourceRavendbIndexes(),
+			"ravendb_license":        dataSourceRavendbLicense(),
+			"ravendb_ongoing_tasks":  dataSourceRavendbOngoingTasks(),
+		},
+		ConfigureContextFunc: providerConfigure,
+	}
+}
+
+// providerConfigure creates the store cache shared by the resources as the provider meta. The
+// stores are closed once terraform stops the provider.
+func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	stores := newStoreCache()
+	if stopCtx, ok := schema.StopContext(ctx); ok {
+		go func() {
+			<-stopCtx.Done()
+			stores.close()
+		}()
 	}
-}
\ No newline at end of file
+	return stores, nil
+}
@@ -0,0 +1,155 @@
+package ravendb
+
+import (
+	"errors"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	internal_operations "github.com/ravendb/terraform-provider-ravendb/operations"
+	"time"
+)
+
+// Readiness holds the timeout of every readiness gate. A gate with no timeout is not checked.
+type Readiness struct {
+	License     time.Duration
+	Certificate time.Duration
+	Topology    time.Duration
+	Databases   time.Duration
+}
+
+func readinessSchema() *schema.Schema {
+	timeout := func(description string) *schema.Schema {
+		return &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Description:  description + " The gate is skipped when unset.",
+			ValidateFunc: validation.IntAtLeast(0),
+		}
+	}
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Gates, beyond the node responding, that have to pass before the nodes are considered deployed.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"license_timeout_sec":     timeout("How long to wait for the license to be activated."),
+				"certificate_timeout_sec": timeout("How long to wait for the cluster certificate to authenticate against the node."),
+				"topology_timeout_sec":    timeout("How long to wait for the node to become a member of the cluster topology."),
+				"databases_timeout_sec":   timeout("How long to wait for the databases to be online."),
+			},
+		},
+	}
+}
+
+func parseReadiness(d *schema.ResourceData) Readiness {
+	var readiness Readiness
+	for _, v := range d.Get("readiness").(*schema.Set).List() {
+		value := v.(map[string]interface{})
+		readiness = Readiness{
+			License:     time.Duration(value["license_timeout_sec"].(int)) * time.Second,
+			Certificate: time.Duration(value["certificate_timeout_sec"].(int)) * time.Second,
+			Topology:    time.Duration(value["topology_timeout_sec"].(int)) * time.Second,
+			Databases:   time.Duration(value["databases_timeout_sec"].(int)) * time.Second,
+		}
+	}
+	return readiness
+}
+
+// waitFor polls check until it reports ready or timeout passes. Errors returned by check are treated as
+// not ready yet, and the last one is reported on timeout.
+func waitFor(gate string, timeout time.Duration, check func() (bool, error)) error {
+	if timeout == 0 {
+		return nil
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		ready, err := check()
+		if ready && err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			message := gate + " was not ready after " + timeout.String()
+			if err != nil {
+				message += ": " + err.Error()
+			}
+			return errors.New(message)
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// waitForNodes checks the license and certificate gates of every node, once the nodes are configured.
+func (sc *ServerConfig) waitForNodes() error {
+	for index, nodeUrl := range sc.Url.List {
+		store, err := getStore(sc, index)
+		if err != nil {
+			return err
+		}
+		err = waitFor("the license of "+nodeUrl, sc.Readiness.License, func() (bool, error) {
+			status := internal_operations.OperationGetLicenseStatus{}
+			err := executeWithRetries(store, &status)
+			if err != nil {
+				return false, err
+			}
+			return status.Result.Type != "" && status.Result.Type != "None" && status.Result.Type != "Invalid" && status.Result.Expired == false, nil
+		})
+		if err != nil {
+			return err
+		}
+		if sc.Unsecured {
+			continue
+		}
+		err = waitFor("the certificate of "+nodeUrl, sc.Readiness.Certificate, func() (bool, error) {
+			whoAmI := internal_operations.OperationWhoAmI{}
+			err := executeWithRetries(store, &whoAmI)
+			if err != nil {
+				return false, err
+			}
+			return whoAmI.Result.Thumbprint != "", nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForCluster checks the topology and databases gates, once the nodes joined the cluster.
+func (sc *ServerConfig) waitForCluster() error {
+	store, err := getStore(sc, 0)
+	if err != nil {
+		return err
+	}
+	for _, nodeUrl := range sc.Url.List {
+		err = waitFor(nodeUrl+" joining the cluster", sc.Readiness.Topology, func() (bool, error) {
+			topology, err := sc.refreshClusterTopology(store)
+			if err != nil {
+				return false, err
+			}
+			return containsValue(topology.Topology.Members, nodeUrl), nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	databases := make([]string, 0, len(sc.Databases)+1)
+	if sc.HealthcheckDatabase != "" {
+		databases = append(databases, sc.HealthcheckDatabase)
+	}
+	for _, database := range sc.Databases {
+		if database.Disabled == false {
+			databases = append(databases, database.Name)
+		}
+	}
+	for _, database := range databases {
+		err = waitFor("database "+database, sc.Readiness.Databases, func() (bool, error) {
+			err := executeWithRetries(store, &internal_operations.OperationGetDatabaseStatistics{Database: database})
+			return err == nil, err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package ravendb
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/ravendb/terraform-provider-ravendb/operations"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	errorAdminOperation        = "error while sending RavenDB admin operation: %s"
+	errorAdminOperationDestroy = "error while sending RavenDB admin operation on destroy: %s"
+)
+
+var adminOperationMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, "RESET"}
+
+func adminOperationRequestSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"method": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringInSlice(adminOperationMethods, false),
+		},
+		"path": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			Description:  "The path of the endpoint, relative to the node url and with its query string, e.g. /admin/configuration/studio.",
+			ValidateFunc: validation.StringMatch(regexp.MustCompile("^/"), "must start with /"),
+		},
+		"body": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			Description:  "The JSON body of the request.",
+			ValidateFunc: validation.StringIsJSON,
+		},
+	}
+}
+
+func resourceRavendbAdminOperation() *schema.Resource {
+	s := adminOperationRequestSchema()
+	s["destroy"] = &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		ForceNew:    true,
+		MaxItems:    1,
+		Description: "The request undoing the operation, sent when the resource is destroyed or replaced.",
+		Elem: &schema.Resource{
+			Schema: adminOperationRequestSchema(),
+		},
+	}
+	s["triggers"] = &schema.Schema{
+		Type:        schema.TypeMap,
+		Optional:    true,
+		ForceNew:    true,
+		Description: "Arbitrary values that send the request again when they change.",
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+	s["response"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The body of the response.",
+	}
+
+	return &schema.Resource{
+		CreateContext: resourceAdminOperationCreate,
+		ReadContext:   schema.NoopContext,
+		UpdateContext: schema.NoopContext,
+		DeleteContext: resourceAdminOperationDelete,
+
+		Schema: withConnectionSchema(s),
+	}
+}
+
+func resourceAdminOperationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	store, err := getStoreFromData(d, meta, "")
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorAdminOperation, err.Error()))
+	}
+
+	operation := operations.OperationSendRequest{
+		Method: d.Get("method").(string),
+		Path:   d.Get("path").(string),
+		Body:   []byte(d.Get("body").(string)),
+	}
+	err = executeWithRetries(store, &operation)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorAdminOperation, err.Error()))
+	}
+
+	d.SetId(operation.Method + " " + operation.Path + " " + strconv.FormatInt(time.Now().UnixNano(), 10))
+	err = d.Set("response", string(operation.Result))
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorAdminOperation, err.Error()))
+	}
+	return nil
+}
+
+func resourceAdminOperationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	list := d.Get("destroy").([]interface{})
+	if len(list) == 0 || list[0] == nil {
+		return nil
+	}
+	request := list[0].(map[string]interface{})
+
+	store, err := getStoreFromData(d, meta, "")
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorAdminOperationDestroy, err.Error()))
+	}
+	err = executeWithRetries(store, &operations.OperationSendRequest{
+		Method: request["method"].(string),
+		Path:   request["path"].(string),
+		Body:   []byte(request["body"].(string)),
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorAdminOperationDestroy, err.Error()))
+	}
+	return nil
+}
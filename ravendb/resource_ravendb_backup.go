@@ -0,0 +1,106 @@
+package ravendb
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/ravendb/terraform-provider-ravendb/operations"
+	"strconv"
+	"time"
+)
+
+const errorBackup = "error while backing up RavenDB database: %s"
+
+// resourceRavendbBackup takes a one-time backup when it is created, and again whenever one of its attributes,
+// e.g. triggers, changes.
+func resourceRavendbBackup() *schema.Resource {
+	s := withConnectionSchema(withBackupSchema(map[string]*schema.Schema{
+		"database": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"triggers": {
+			Type:        schema.TypeMap,
+			Optional:    true,
+			Description: "Arbitrary values that take a new backup when changed, e.g. the version about to be deployed.",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"timeout_minutes": {
+			Type:     schema.TypeInt,
+			Optional: true,
+			Default:  60,
+		},
+		"responsible_node": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The tag of the node that took the backup.",
+		},
+		"result": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The result the server reported for the backup operation, as JSON.",
+		},
+	}))
+	for _, value := range s {
+		if !value.Computed {
+			value.ForceNew = true
+		}
+	}
+
+	return &schema.Resource{
+		CreateContext: resourceBackupCreate,
+		ReadContext:   schema.NoopContext,
+		DeleteContext: schema.NoopContext,
+
+		Schema: s,
+	}
+}
+
+func resourceBackupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	database := d.Get("database").(string)
+	configuration, err := parseBackup(d)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorBackup, err.Error()))
+	}
+
+	store, err := getStoreFromData(d, meta, database)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorBackup, err.Error()))
+	}
+	err = resolveBackupKey(d, store, &configuration)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorBackup, err.Error()))
+	}
+
+	operation := operations.OperationBackup{
+		Database:      database,
+		Configuration: configuration,
+	}
+	err = executeWithRetries(store, &operation)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorBackup, err.Error()))
+	}
+
+	timeout := time.Duration(d.Get("timeout_minutes").(int)) * time.Minute
+	state, err := waitForOperation(store, database, operation.Result.OperationId, timeout)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorBackup, err.Error()))
+	}
+
+	d.SetId(database + "/backup/operation/" + strconv.FormatInt(operation.Result.OperationId, 10))
+	values := map[string]interface{}{
+		"responsible_node": operation.Result.ResponsibleNode,
+		"result":           string(state.Result),
+	}
+	for key, value := range values {
+		err = d.Set(key, value)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf(errorBackup, err.Error()))
+		}
+	}
+
+	return nil
+}
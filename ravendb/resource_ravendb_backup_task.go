@@ -0,0 +1,249 @@
+package ravendb
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/ravendb/terraform-provider-ravendb/operations"
+	"strconv"
+	"strings"
+)
+
+const (
+	errorBackupPut    = "error while configuring RavenDB backup task: %s"
+	errorBackupRead   = "error reading RavenDB backup task: %s"
+	errorBackupDelete = "error deleting RavenDB backup task: %s"
+)
+
+func resourceRavendbBackupTask() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceBackupTaskPut,
+		ReadContext:   resourceBackupTaskRead,
+		UpdateContext: resourceBackupTaskPut,
+		DeleteContext: resourceBackupTaskDelete,
+
+		Schema: withConnectionSchema(withBackupSchema(map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"full_backup_frequency": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The cron expression full backups are taken at.",
+			},
+			"incremental_backup_frequency": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The cron expression incremental backups are taken at.",
+			},
+			"disabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"mentor_node": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The tag of the node preferred to run the backups.",
+			},
+			"retention": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"minimum_backup_age_to_keep_hours": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							Description:  "Backups older than this are deleted once a newer full backup exists.",
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+					},
+				},
+			},
+			"task_id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		})),
+	}
+}
+
+func parseBackupConfiguration(d *schema.ResourceData) (operations.PeriodicBackupConfiguration, error) {
+	configuration := operations.PeriodicBackupConfiguration{
+		TaskId:                     int64(d.Get("task_id").(int)),
+		Name:                       d.Get("name").(string),
+		Disabled:                   d.Get("disabled").(bool),
+		MentorNode:                 d.Get("mentor_node").(string),
+		FullBackupFrequency:        d.Get("full_backup_frequency").(string),
+		IncrementalBackupFrequency: d.Get("incremental_backup_frequency").(string),
+	}
+
+	var err error
+	configuration.BackupConfiguration, err = parseBackup(d)
+	if err != nil {
+		return configuration, err
+	}
+
+	configuration.RetentionPolicy = &operations.RetentionPolicy{Disabled: true}
+	if list := d.Get("retention").(*schema.Set).List(); len(list) > 0 {
+		retention := list[0].(map[string]interface{})
+		age := formatTimeSpan(retention["minimum_backup_age_to_keep_hours"].(int) * 3600)
+		configuration.RetentionPolicy = &operations.RetentionPolicy{MinimumBackupAgeToKeep: &age}
+	}
+
+	return configuration, nil
+}
+
+// formatTimeSpan formats seconds as a .NET TimeSpan, d.hh:mm:ss.
+func formatTimeSpan(seconds int) string {
+	return fmt.Sprintf("%d.%02d:%02d:%02d", seconds/86400, seconds/3600%24, seconds/60%60, seconds%60)
+}
+
+// parseTimeSpan returns the whole seconds of a .NET TimeSpan, formatted as [d.]hh:mm:ss[.fffffff].
+func parseTimeSpan(span string) (int, error) {
+	parts := strings.Split(span, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid time span %s", span)
+	}
+	days := 0
+	dayAndHours := strings.SplitN(parts[0], ".", 2)
+	if len(dayAndHours) == 2 {
+		var err error
+		days, err = strconv.Atoi(dayAndHours[0])
+		if err != nil {
+			return 0, err
+		}
+	}
+	hours, err := strconv.Atoi(dayAndHours[len(dayAndHours)-1])
+	if err != nil {
+		return 0, err
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, err
+	}
+	seconds, err := strconv.Atoi(strings.SplitN(parts[2], ".", 2)[0])
+	if err != nil {
+		return 0, err
+	}
+	return ((days*24+hours)*60+minutes)*60 + seconds, nil
+}
+
+func resourceBackupTaskPut(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	database := d.Get("database").(string)
+	configuration, err := parseBackupConfiguration(d)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorBackupPut, err.Error()))
+	}
+
+	store, err := getStoreFromData(d, meta, database)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorBackupPut, err.Error()))
+	}
+	err = resolveBackupKey(d, store, &configuration.BackupConfiguration)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorBackupPut, err.Error()))
+	}
+
+	operation := operations.OperationPutPeriodicBackup{
+		Database:      database,
+		Configuration: configuration,
+	}
+	err = executeWithRetries(store, &operation)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorBackupPut, err.Error()))
+	}
+
+	d.SetId(database + "/backup/" + strconv.FormatInt(operation.Result.TaskId, 10))
+	err = d.Set("task_id", int(operation.Result.TaskId))
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorBackupPut, err.Error()))
+	}
+
+	return resourceBackupTaskRead(ctx, d, meta)
+}
+
+func resourceBackupTaskRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	database := d.Get("database").(string)
+	store, err := getStoreFromData(d, meta, database)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorBackupRead, err.Error()))
+	}
+
+	record := operations.OperationGetDatabaseRecord{Database: database}
+	err = executeWithRetries(store, &record)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorBackupRead, err.Error()))
+	}
+	backups, err := record.Result.PeriodicBackups()
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorBackupRead, err.Error()))
+	}
+
+	var backup *operations.PeriodicBackupConfiguration
+	for i := range backups {
+		if backups[i].TaskId == int64(d.Get("task_id").(int)) {
+			backup = &backups[i]
+		}
+	}
+	if backup == nil {
+		d.SetId("")
+		return nil
+	}
+
+	values := map[string]interface{}{
+		"name":                         backup.Name,
+		"full_backup_frequency":        backup.FullBackupFrequency,
+		"incremental_backup_frequency": backup.IncrementalBackupFrequency,
+		"disabled":                     backup.Disabled,
+		"mentor_node":                  backup.MentorNode,
+	}
+	for key, value := range flattenBackup(d, backup.BackupConfiguration) {
+		values[key] = value
+	}
+
+	var retention []interface{}
+	if backup.RetentionPolicy != nil && !backup.RetentionPolicy.Disabled && backup.RetentionPolicy.MinimumBackupAgeToKeep != nil {
+		age, err := parseTimeSpan(*backup.RetentionPolicy.MinimumBackupAgeToKeep)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf(errorBackupRead, err.Error()))
+		}
+		retention = append(retention, map[string]interface{}{"minimum_backup_age_to_keep_hours": age / 3600})
+	}
+	values["retention"] = retention
+
+	for key, value := range values {
+		err = d.Set(key, value)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf(errorBackupRead, err.Error()))
+		}
+	}
+
+	return nil
+}
+
+func resourceBackupTaskDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	database := d.Get("database").(string)
+	store, err := getStoreFromData(d, meta, database)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorBackupDelete, err.Error()))
+	}
+
+	err = executeWithRetries(store, &operations.OperationDeleteOngoingTask{
+		Database: database,
+		TaskId:   int64(d.Get("task_id").(int)),
+		TaskType: "Backup",
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorBackupDelete, err.Error()))
+	}
+	return nil
+}
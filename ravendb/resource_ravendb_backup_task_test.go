@@ -0,0 +1,24 @@
+package ravendb
+
+import "testing"
+
+func TestTimeSpanRoundTrip(t *testing.T) {
+	for _, seconds := range []int{1, 59, 60, 3600, 3661, 23 * 3600, 86400, 90061, 30 * 86400} {
+		span := formatTimeSpan(seconds)
+		parsed, err := parseTimeSpan(span)
+		if err != nil {
+			t.Fatalf("%s: %s", span, err)
+		}
+		if parsed != seconds {
+			t.Errorf("%s: expected %d seconds, got %d", span, seconds, parsed)
+		}
+	}
+	seconds, err := parseTimeSpan("12:00:00")
+	if err != nil || seconds != 12*3600 {
+		t.Errorf("expected 12 hours without a day part, got %d seconds (%v)", seconds, err)
+	}
+	seconds, err = parseTimeSpan("00:10:00.5000000")
+	if err != nil || seconds != 600 {
+		t.Errorf("expected 600 seconds with a fraction part, got %d (%v)", seconds, err)
+	}
+}
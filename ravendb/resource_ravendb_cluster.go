@@ -0,0 +1,181 @@
+package ravendb
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"net/url"
+)
+
+const (
+	errorClusterCreate = "error while configuring RavenDB cluster: %s"
+	errorClusterRead   = "error reading RavenDB cluster: %s"
+)
+
+// resourceRavendbCluster manages the cluster level state - membership, the healthcheck database and the
+// databases - of nodes deployed by ravendb_node, so replacing a node doesn't touch the rest of the cluster.
+func resourceRavendbCluster() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceClusterCreate,
+		ReadContext:   resourceClusterRead,
+		UpdateContext: resourceClusterUpdate,
+		DeleteContext: schema.NoopContext,
+		CustomizeDiff: customizeHealthcheckDatabase,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceClusterImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"nodes": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "The urls of the nodes that form the cluster. The first node adds the others to its cluster.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				},
+			},
+			"certificate": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				Description:  "The cluster certificate the nodes were deployed with, used to authenticate against them.",
+				ValidateFunc: validation.StringIsBase64,
+			},
+			"tls": tlsSchema(),
+			"unsecured": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"ignore_embedded_databases": ignoreEmbeddedDatabasesSchema(),
+			"hard_delete_databases":     hardDeleteDatabasesSchema(),
+			"database": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The database name to check whether he is alive or not.",
+			},
+			"healthcheck_database": healthcheckDatabaseSchema(),
+			"databases":            databasesSchema(),
+			"index_swap_status":    indexSwapStatusSchema(),
+			"client_certificates":  clientCertificatesSchema(),
+			"members": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "The url of every node in the cluster topology by its tag.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func parseClusterData(d *schema.ResourceData) (ServerConfig, error) {
+	var sc ServerConfig
+	sc.Unsecured = d.Get("unsecured").(bool)
+
+	cert, err := base64.StdEncoding.DecodeString(d.Get("certificate").(string))
+	if err != nil {
+		return sc, err
+	}
+	if allZero(cert) == false {
+		sc.ClusterCertificate = cert
+	}
+	sc.TLS, err = parseTLSOptions(d)
+	if err != nil {
+		return sc, err
+	}
+
+	nodes := d.Get("nodes").([]interface{})
+	sc.Url.List = make([]string, len(nodes))
+	sc.Hosts = make([]string, len(nodes))
+	for i, node := range nodes {
+		sc.Url.List[i] = node.(string)
+		u, err := url.Parse(node.(string))
+		if err != nil {
+			return sc, err
+		}
+		sc.Hosts[i] = u.Hostname()
+	}
+
+	sc.Healthcheck = parseHealthcheckDatabase(d)
+	if dbName, ok := d.GetOk("database"); ok {
+		sc.HealthcheckDatabase = sc.Healthcheck.NamePrefix + dbName.(string)
+	}
+	sc.Databases = parseDatabases(d)
+	sc.ClientCertificates, err = parseClientCertificates(d)
+	if err != nil {
+		return sc, err
+	}
+
+	return sc, nil
+}
+
+func resourceClusterCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	sc, err := parseClusterData(d)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorClusterCreate, err.Error()))
+	}
+	sc.stores = meta.(*storeCache)
+
+	id, err := sc.configureCluster()
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorClusterCreate, err.Error()))
+	}
+	d.SetId(id)
+
+	return readClusterState(d, sc)
+}
+
+func resourceClusterUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	sc, err := parseClusterData(d)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorClusterCreate, err.Error()))
+	}
+	sc.stores = meta.(*storeCache)
+
+	_, err = sc.configureCluster()
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorClusterCreate, err.Error()))
+	}
+	err = sc.deleteRemovedDatabases(d)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorClusterCreate, err.Error()))
+	}
+
+	return readClusterState(d, sc)
+}
+
+func resourceClusterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	sc, err := parseClusterData(d)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorClusterRead, err.Error()))
+	}
+	sc.stores = meta.(*storeCache)
+
+	return readClusterState(d, sc)
+}
+
+func readClusterState(d *schema.ResourceData, sc ServerConfig) diag.Diagnostics {
+	store, err := getStore(&sc, 0)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorClusterRead, err.Error()))
+	}
+	topology, err := sc.getClusterTopology(store)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorClusterRead, err.Error()))
+	}
+	err = d.Set("members", topology.Topology.Members)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorClusterRead, err.Error()))
+	}
+	err = sc.setIndexSwapStatus(d)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorClusterRead, err.Error()))
+	}
+	return nil
+}
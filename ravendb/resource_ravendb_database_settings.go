@@ -0,0 +1,119 @@
+package ravendb
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/ravendb/ravendb-go-client"
+	"github.com/ravendb/terraform-provider-ravendb/operations"
+)
+
+const (
+	errorDatabaseSettingsPut    = "error while configuring RavenDB database settings: %s"
+	errorDatabaseSettingsRead   = "error reading RavenDB database settings: %s"
+	errorDatabaseSettingsDelete = "error deleting RavenDB database settings: %s"
+)
+
+func resourceRavendbDatabaseSettings() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDatabaseSettingsPut,
+		ReadContext:   resourceDatabaseSettingsRead,
+		UpdateContext: resourceDatabaseSettingsPut,
+		DeleteContext: resourceDatabaseSettingsDelete,
+
+		Schema: withConnectionSchema(map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "An existing database, created outside of this resource.",
+			},
+			"settings": {
+				Type:        schema.TypeMap,
+				Required:    true,
+				Description: "The customized settings of the database. Settings missing from the map are reset to the server defaults.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"reload": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Reloads the database when its settings change, as settings only take effect when the database is loaded.",
+			},
+		}),
+	}
+}
+
+func resourceDatabaseSettingsPut(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	database := d.Get("database").(string)
+	store, err := getStoreFromData(d, meta, "")
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorDatabaseSettingsPut, err.Error()))
+	}
+
+	err = putDatabaseSettings(d, store, toStringMap(d.Get("settings").(map[string]interface{})))
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorDatabaseSettingsPut, err.Error()))
+	}
+	d.SetId(database)
+
+	return resourceDatabaseSettingsRead(ctx, d, meta)
+}
+
+func resourceDatabaseSettingsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	store, err := getStoreFromData(d, meta, "")
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorDatabaseSettingsRead, err.Error()))
+	}
+
+	record := operations.OperationGetDatabaseRecord{Database: d.Get("database").(string)}
+	err = executeWithRetries(store, &record)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorDatabaseSettingsRead, err.Error()))
+	}
+	if record.Result == nil {
+		d.SetId("")
+		return nil
+	}
+
+	settings := map[string]string{}
+	if current, ok := record.Result["Settings"].(map[string]interface{}); ok {
+		for key, value := range current {
+			settings[key] = fmt.Sprintf("%v", value)
+		}
+	}
+	err = d.Set("settings", settings)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorDatabaseSettingsRead, err.Error()))
+	}
+	return nil
+}
+
+// resourceDatabaseSettingsDelete resets the database to the server defaults. The database itself is kept.
+func resourceDatabaseSettingsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	store, err := getStoreFromData(d, meta, "")
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorDatabaseSettingsDelete, err.Error()))
+	}
+
+	err = putDatabaseSettings(d, store, map[string]string{})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorDatabaseSettingsDelete, err.Error()))
+	}
+	return nil
+}
+
+func putDatabaseSettings(d *schema.ResourceData, store *ravendb.DocumentStore, settings map[string]string) error {
+	database := d.Get("database").(string)
+	changed, err := updateDatabaseSettings(store, Database{Name: database, Settings: settings})
+	if err != nil {
+		return err
+	}
+	if changed && d.Get("reload").(bool) {
+		return reloadDatabases(store, []string{database})
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package ravendb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/ravendb/terraform-provider-ravendb/operations"
+	"strconv"
+	"time"
+)
+
+const errorMigration = "error while migrating data into RavenDB: %s"
+
+// resourceRavendbMigration imports the data of an external source once, when it is created. It has nothing to
+// update, so a change to any of its attributes migrates again.
+func resourceRavendbMigration() *schema.Resource {
+	s := withConnectionSchema(map[string]*schema.Schema{
+		"database": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The database to import the data into.",
+		},
+		"source": {
+			Type:     schema.TypeList,
+			Required: true,
+			ForceNew: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"type": {
+						Type:         schema.TypeString,
+						Required:     true,
+						ForceNew:     true,
+						Description:  "The kind of source to migrate from - ravendb, mongodb, cosmosdb.",
+						ValidateFunc: validation.StringInSlice([]string{"ravendb", "mongodb", "cosmosdb"}, false),
+					},
+					"server_url": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						ForceNew:     true,
+						Description:  "The url of the source RavenDB server.",
+						ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+					},
+					"build_major_version": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						ForceNew:     true,
+						Description:  "The major version of the source RavenDB server - V2, V30, V35, or V4 for 4.x and later. Required when migrating from RavenDB.",
+						ValidateFunc: validation.StringInSlice([]string{"V2", "V30", "V35", "V4"}, false),
+					},
+					"build_version": {
+						Type:         schema.TypeInt,
+						Optional:     true,
+						ForceNew:     true,
+						Description:  "The build number of the source RavenDB server, e.g. 54074. Required when migrating from RavenDB.",
+						ValidateFunc: validation.IntAtLeast(1),
+					},
+					"operate_on_types": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						ForceNew:    true,
+						Default:     "Documents, RevisionDocuments, Indexes, Identities",
+						Description: "The items migrated from the source RavenDB database, as the flags of the server DatabaseItemType.",
+					},
+					"connection_string": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						ForceNew:    true,
+						Sensitive:   true,
+						Description: "The connection string of the source MongoDB/CosmosDB database.",
+					},
+					"database_name": {
+						Type:     schema.TypeString,
+						Required: true,
+						ForceNew: true,
+					},
+					"migrator_path": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						ForceNew:    true,
+						Description: "The full path of Raven.Migrator on the server, required for MongoDB/CosmosDB sources.",
+					},
+				},
+			},
+		},
+		"timeout_minutes": {
+			Type:     schema.TypeInt,
+			Optional: true,
+			ForceNew: true,
+			Default:  60,
+		},
+		"result": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The result the server reported for the migration operation, as JSON.",
+		},
+	})
+	for _, value := range s {
+		if !value.Computed {
+			value.ForceNew = true
+		}
+	}
+
+	return &schema.Resource{
+		CreateContext: resourceMigrationCreate,
+		ReadContext:   schema.NoopContext,
+		DeleteContext: schema.NoopContext,
+
+		Schema: s,
+	}
+}
+
+func resourceMigrationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	database := d.Get("database").(string)
+	source := d.Get("source").([]interface{})[0].(map[string]interface{})
+
+	operation := operations.OperationMigrate{
+		Database: database,
+	}
+	var err error
+	operation.Path, operation.Configuration, err = migrationConfiguration(source)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorMigration, err.Error()))
+	}
+
+	store, err := getStoreFromData(d, meta, database)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorMigration, err.Error()))
+	}
+
+	err = executeWithRetries(store, &operation)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorMigration, err.Error()))
+	}
+
+	timeout := time.Duration(d.Get("timeout_minutes").(int)) * time.Minute
+	state, err := waitForOperation(store, database, operation.Result.OperationId, timeout)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorMigration, err.Error()))
+	}
+
+	d.SetId(database + "/" + strconv.FormatInt(operation.Result.OperationId, 10))
+	err = d.Set("result", string(state.Result))
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorMigration, err.Error()))
+	}
+
+	return nil
+}
+
+// migrationConfiguration returns the migrator path and the request body migrating source. RavenDB sources take a
+// SingleDatabaseMigrationConfiguration, the others the configuration of Raven.Migrator.
+func migrationConfiguration(source map[string]interface{}) (string, map[string]interface{}, error) {
+	if source["type"].(string) == "ravendb" {
+		if source["server_url"].(string) == "" || source["build_major_version"].(string) == "" || source["build_version"].(int) == 0 {
+			return "", nil, errors.New("server_url, build_major_version and build_version are required when migrating from RavenDB")
+		}
+		return "ravendb", map[string]interface{}{
+			"ServerUrl":         source["server_url"].(string),
+			"BuildMajorVersion": source["build_major_version"].(string),
+			"BuildVersion":      source["build_version"].(int),
+			"MigrationSettings": map[string]interface{}{
+				"DatabaseName":   source["database_name"].(string),
+				"OperateOnTypes": source["operate_on_types"].(string),
+			},
+		}, nil
+	}
+
+	if source["migrator_path"].(string) == "" || source["connection_string"].(string) == "" {
+		return "", nil, errors.New("migrator_path and connection_string are required when migrating from " + source["type"].(string))
+	}
+	databaseType := "MongoDB"
+	if source["type"].(string) == "cosmosdb" {
+		databaseType = "CosmosDB"
+	}
+	return "", map[string]interface{}{
+		"DatabaseTypeName": databaseType,
+		"MigratorFullPath": source["migrator_path"].(string),
+		"InputConfiguration": map[string]interface{}{
+			"Command":          "import",
+			"ConnectionString": source["connection_string"].(string),
+			"DatabaseName":     source["database_name"].(string),
+		},
+	}, nil
+}
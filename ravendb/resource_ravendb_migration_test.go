@@ -0,0 +1,67 @@
+package ravendb
+
+import (
+	"encoding/json"
+	"github.com/ravendb/ravendb-go-client"
+	"github.com/ravendb/terraform-provider-ravendb/operations"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestRavenDbMigrationBody(t *testing.T) {
+	source := map[string]interface{}{
+		"type":                "ravendb",
+		"server_url":          "http://legacy.example.com:8080",
+		"database_name":       "Orders",
+		"build_major_version": "V35",
+		"build_version":       35282,
+		"operate_on_types":    "Documents, Indexes",
+		"connection_string":   "",
+		"migrator_path":       "",
+	}
+	operation := operations.OperationMigrate{Database: "Orders"}
+	var err error
+	operation.Path, operation.Configuration, err = migrationConfiguration(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	command, err := operation.GetCommand(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request, err := command.CreateRequest(&ravendb.ServerNode{URL: "https://a.example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request.URL.String() != "https://a.example.com/databases/Orders/admin/smuggler/migrate/ravendb" {
+		t.Errorf("unexpected url %s", request.URL)
+	}
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actual map[string]interface{}
+	err = json.Unmarshal(body, &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"ServerUrl":         "http://legacy.example.com:8080",
+		"BuildMajorVersion": "V35",
+		"BuildVersion":      float64(35282),
+		"MigrationSettings": map[string]interface{}{
+			"DatabaseName":   "Orders",
+			"OperateOnTypes": "Documents, Indexes",
+		},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected the body %v, got %s", expected, body)
+	}
+
+	source["build_version"] = 0
+	_, _, err = migrationConfiguration(source)
+	if err == nil {
+		t.Error("expected build_version to be required")
+	}
+}
@@ -0,0 +1,548 @@
+package ravendb
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"time"
+)
+
+const (
+	errorNodeCreate = "error while deploying RavenDB node: %s"
+	errorNodeRead   = "error reading RavenDB node: %s"
+	errorNodeDelete = "error deleting RavenDB node: %s"
+)
+
+// nodeSchema holds the attributes that install and configure a single RavenDB server, shared by
+// ravendb_node and ravendb_server.
+func nodeSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"certificate": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  "The cluster certificate file that is used by RavenDB for server side authentication.",
+			ValidateFunc: validation.StringIsBase64,
+		},
+		"license": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			DefaultFunc:  schema.EnvDefaultFunc("RAVENDB_LICENSE", nil),
+			Description:  "The license that will be used for the setup of the RavenDB cluster, base64 encoded. Read from RAVENDB_LICENSE when unset, and overrides the license of the setup package.",
+			ValidateFunc: validation.StringIsBase64,
+		},
+		"setup_package":         setupPackageSchema(),
+		"tls":                   tlsSchema(),
+		"setup_package_archive": setupPackageArchiveSchema(),
+		"setup_package_sha256":  setupPackageHashSchema(),
+		"outdated_certificates": outdatedCertificatesSchema(),
+		"offline_license": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Activates the license without internet access, and keeps the servers from contacting the RavenDB license server.",
+		},
+		"package": {
+			Type:     schema.TypeSet,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"version": {
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: "The RavenDB version to use for the cluster, or a constraint such as `~> 5.4` resolved at plan time.",
+					},
+					"arch": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "Operating system architecture name - amd64, arm64, arm32",
+					},
+					"channel": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						Description:  "The release channel the package is downloaded from. Only daily, the default, is available.",
+						ValidateFunc: validation.StringInSlice([]string{PACKAGE_CHANNEL_DAILY}, false),
+					},
+					"skip_url_check": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Description: "Don't check that the package can be downloaded, for planning without internet access. The hosts still download it on apply.",
+					},
+					"distribution": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						Default:      PACKAGE_DISTRIBUTION_PULL,
+						Description:  "How the package gets to the hosts - pull: every host downloads it, push: the machine running terraform downloads it once and uploads it to the hosts.",
+						ValidateFunc: validation.StringInSlice([]string{PACKAGE_DISTRIBUTION_PULL, PACKAGE_DISTRIBUTION_PUSH}, false),
+					},
+					"cache_dir": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "A directory of the machine running terraform the pushed packages are kept in, so later applies don't download them again.",
+					},
+				},
+			},
+		},
+		"resolved_version": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The version a package.version constraint was resolved to at plan time, or on apply with skip_url_check.",
+		},
+		"deploy_mode": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  DEPLOY_MODE_PROVISION_AND_CONFIGURE,
+			Description: "provision_and_configure installs the package and configures the hosts. configure_only skips the installation, for RavenDB " +
+				"installed by another tool (e.g. a Packer image). cluster_only doesn't touch the hosts and only manages the cluster and its databases.",
+			ValidateFunc: validation.StringInSlice([]string{DEPLOY_MODE_PROVISION_AND_CONFIGURE, DEPLOY_MODE_CONFIGURE_ONLY, DEPLOY_MODE_CLUSTER_ONLY}, false),
+		},
+		"unsecured": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Whatever to allow to run RavenDB in unsecured mode. This is ***NOT*** recommended!",
+		},
+		"settings_override": {
+			Type:     schema.TypeMap,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"settings_merge_strategy": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  SETTINGS_MERGE,
+			Description: "How settings_override is applied on the settings.json of the nodes. merge keeps every key already there, including the ones " +
+				"removed from settings_override. replace rewrites settings.json from the provider settings alone. preserve_unknown removes the keys " +
+				"removed from settings_override and keeps the ones set outside of Terraform.",
+			ValidateFunc: validation.StringInSlice([]string{SETTINGS_MERGE, SETTINGS_MERGE_REPLACE, SETTINGS_MERGE_PRESERVE_UNKNOWN}, false),
+		},
+		"assets": {
+			Type:       schema.TypeMap,
+			Optional:   true,
+			Deprecated: "Use asset blocks, which also set the mode and ownership of the files.",
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validation.StringIsBase64,
+			},
+		},
+		"asset": assetSchema(),
+		"environment": {
+			Type:         schema.TypeMap,
+			Optional:     true,
+			Description:  "RAVEN_ prefixed environment variables of the ravendb service, written to " + environmentFilePath + ".",
+			ValidateFunc: validateEnvironment,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"connection": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      CONNECTION_SSH,
+			Description:  "How the hosts are reached: ssh, or local to run every step on the machine running Terraform, which then must be the only host.",
+			ValidateFunc: validation.StringInSlice([]string{CONNECTION_SSH, CONNECTION_LOCAL}, false),
+		},
+		"ssh": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Required unless connection is local.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"user": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"pem": {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validation.StringIsBase64,
+					},
+					"reboot_timeout_sec": {
+						Type:         schema.TypeInt,
+						Optional:     true,
+						Default:      600,
+						Description:  "How long to wait for a host that rebooted during the deploy to come back before resuming. 0 fails the deploy instead.",
+						ValidateFunc: validation.IntAtLeast(0),
+					},
+					"host_keys": {
+						Type:         schema.TypeMap,
+						Optional:     true,
+						Description:  "The host key of every host, by host, as a public key (e.g. `ssh-ed25519 AAAA...`) or a SHA256 fingerprint. Connecting to a host presenting another key fails.",
+						ValidateFunc: validateHostKeys,
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+					},
+					"known_hosts_file": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "A known_hosts file the keys of the hosts missing from host_keys are checked against. Host keys aren't checked when neither is set.",
+					},
+				},
+			},
+		},
+		"monitoring":          monitoringSchema(),
+		"logging":             loggingSchema(),
+		"traffic_watch":       trafficWatchSchema(),
+		"cluster_observer":    clusterObserverSchema(),
+		"unattended_upgrades": unattendedUpgradesSchema(),
+		"clock_sync":          clockSyncSchema(),
+		"preflight":           preflightSchema(),
+		"deploy_retry":        deployRetrySchema(),
+		"rollback_on_failure": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Purges RavenDB from the hosts this apply installed it on when the deployment fails, instead of leaving them half configured.",
+		},
+		"manage_hostname": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Sets the hostname of every host to the fully qualified name in its node url, with hostnamectl and /etc/hosts.",
+		},
+		"debug_bundle_directory": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Local directory to write a debug bundle (service logs, settings.json, disk and memory usage) to when a node fails to deploy.",
+		},
+	}
+}
+
+// withNodeSchema adds the node attributes to a resource schema.
+func withNodeSchema(s map[string]*schema.Schema) map[string]*schema.Schema {
+	for key, value := range nodeSchema() {
+		s[key] = value
+	}
+	return s
+}
+
+func resourceRavendbNode() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceNodeCreate,
+		ReadContext:   resourceNodeRead,
+		UpdateContext: resourceNodeCreate,
+		DeleteContext: resourceNodeDelete,
+		CustomizeDiff: resourceNodeCustomizeDiff,
+
+		Schema: withNodeSchema(map[string]*schema.Schema{
+			"host": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The ip address or hostname of the machine RavenDB is installed on.",
+				ValidateFunc: validateHost,
+			},
+			"url": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The public url of the node.",
+				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+			},
+			"http_port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IsPortNumber,
+			},
+			"tcp_port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IsPortNumber,
+			},
+			"version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"http_url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"tcp_url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"settings": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "The settings.json of the node, without the values derived from the urls.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"deployment_report": deploymentReportSchema(),
+		}),
+	}
+}
+
+// parseNodeConfig reads the attributes of nodeSchema.
+func parseNodeConfig(d *schema.ResourceData) (ServerConfig, error) {
+	var sc ServerConfig
+	sc.report = newDeploymentReport()
+	sc.installed = &installedHosts{}
+	sc.packages = &packageCache{}
+
+	if unsecured, ok := d.GetOk("unsecured"); ok {
+		sc.Unsecured = unsecured.(bool)
+	}
+	if quickstart, ok := d.GetOk("quickstart"); ok && quickstart.(bool) {
+		sc.Unsecured = true
+	}
+
+	certBas64 := d.Get("certificate").(string)
+	cert, err := base64.StdEncoding.DecodeString(certBas64)
+	if err != nil {
+		return sc, err
+	}
+	if allZero(cert) == false {
+		sc.ClusterCertificate = cert
+	}
+	sc.TLS, err = parseTLSOptions(d)
+	if err != nil {
+		return sc, err
+	}
+	sc.SetupPackage, err = parseSetupPackage(d)
+	if err != nil {
+		return sc, err
+	}
+
+	licenseBas64 := d.Get("license").(string)
+	license, err := base64.StdEncoding.DecodeString(licenseBas64)
+	if err != nil {
+		return sc, err
+	}
+	if allZero(license) == false {
+		sc.License = license
+	} else if sc.SetupPackage == nil {
+		return sc, errors.New("license is required, as an attribute or through RAVENDB_LICENSE, unless it is taken from setup_package")
+	}
+	sc.OfflineLicense = d.Get("offline_license").(bool)
+
+	sc.DeployMode = d.Get("deploy_mode").(string)
+	packageSet := d.Get("package").(*schema.Set).List()
+	if len(packageSet) == 0 && sc.DeployMode == DEPLOY_MODE_PROVISION_AND_CONFIGURE {
+		return sc, errors.New("package is required when deploy_mode is " + DEPLOY_MODE_PROVISION_AND_CONFIGURE)
+	}
+	for _, v := range packageSet {
+		value := v.(map[string]interface{})
+		sc.Package.Version = value["version"].(string)
+		sc.Package.Arch = value["arch"].(string)
+		sc.Package.Channel = value["channel"].(string)
+		sc.Package.SkipUrlCheck = value["skip_url_check"].(bool)
+		if isVersionConstraint(sc.Package.Version) {
+			sc.Package.Version, err = resolvedPackageVersion(d, sc.Package)
+			if err != nil {
+				return sc, err
+			}
+		}
+		sc.Package.Distribution = value["distribution"].(string)
+		sc.Package.CacheDir = value["cache_dir"].(string)
+		err := validatePackage(&sc)
+		if err != nil {
+			return sc, err
+		}
+	}
+
+	sc.Assets, err = parseAssets(d)
+	if err != nil {
+		return sc, err
+	}
+	sc.Environment = map[string]string{}
+	for name, value := range d.Get("environment").(map[string]interface{}) {
+		sc.Environment[name] = value.(string)
+	}
+	settings := d.Get("settings_override").(map[string]interface{})
+	sc.Settings = make(map[string]interface{})
+	for k, v := range settings {
+		sc.Settings[k] = v.(string)
+	}
+	sc.SettingsMerge = d.Get("settings_merge_strategy").(string)
+	// the keys removed from settings_override since the last apply, dropped from the nodes by preserve_unknown
+	previousSettings, _ := d.GetChange("settings_override")
+	for key := range previousSettings.(map[string]interface{}) {
+		if _, ok := settings[key]; !ok {
+			sc.RemovedSettings = append(sc.RemovedSettings, key)
+		}
+	}
+
+	sc.Connection = d.Get("connection").(string)
+	sshSet := d.Get("ssh").(*schema.Set).List()
+	if sc.Connection == CONNECTION_LOCAL {
+		sc.dial = dialLocal
+	} else if len(sshSet) == 0 {
+		return sc, errors.New("ssh is required unless connection is " + CONNECTION_LOCAL)
+	}
+	for _, v := range sshSet {
+		value := v.(map[string]interface{})
+		sc.SSH.User = value["user"].(string)
+		sc.SSH.RebootTimeout = time.Duration(value["reboot_timeout_sec"].(int)) * time.Second
+		sc.SSH.HostKeys = toStringMap(value["host_keys"].(map[string]interface{}))
+		sc.SSH.KnownHostsFile = value["known_hosts_file"].(string)
+		pemBase64 := value["pem"].(string)
+		pem, err := base64.StdEncoding.DecodeString(pemBase64)
+		if err != nil {
+			return sc, err
+		}
+		sc.SSH.Pem = pem
+	}
+
+	sc.Monitoring = parseMonitoring(d)
+	sc.Logging = parseLogging(d)
+	sc.TrafficWatch = parseTrafficWatch(d)
+	sc.ClusterObserver = parseClusterObserver(d)
+	sc.UnattendedUpgrades = d.Get("unattended_upgrades").(string)
+	sc.ClockSync = parseClockSync(d)
+	sc.Preflight = parsePreflight(d)
+	sc.DeployRetry = parseDeployRetry(d)
+	sc.RollbackOnFailure = d.Get("rollback_on_failure").(bool)
+	sc.ManageHostname = d.Get("manage_hostname").(bool)
+
+	if debugBundleDir, ok := d.GetOk("debug_bundle_directory"); ok {
+		sc.DebugBundleDir = debugBundleDir.(string)
+	}
+
+	if sc.ClusterCertificate != nil && sc.Unsecured == true {
+		return sc, fmt.Errorf("expected unsecure to be ture. certificate should be added when using secure mode")
+	}
+
+	return sc, nil
+}
+
+// parsePorts reads http_port and tcp_port, falling back to the defaults of the security mode.
+func parsePorts(value map[string]interface{}, unsecured bool) (int, int) {
+	var httpPort, tcpPort int
+	if port, ok := value["http_port"]; ok {
+		httpPort = port.(int)
+	} else {
+		httpPort = DEFAULT_SECURE_RAVENDB_HTTP_PORT
+		if unsecured {
+			httpPort = DEFAULT_USECURED_RAVENDB_HTTP_PORT
+		}
+	}
+
+	if port, ok := value["tcp_port"]; ok {
+		tcpPort = port.(int)
+	} else {
+		tcpPort = DEFAULT_SECURE_RAVENDB_TCP_PORT
+		if unsecured {
+			tcpPort = DEFAULT_UNSECURED_RAVENDB_TCP_PORT
+		}
+	}
+	return httpPort, tcpPort
+}
+
+func parseNodeData(d *schema.ResourceData) (ServerConfig, error) {
+	sc, err := parseNodeConfig(d)
+	if err != nil {
+		return sc, err
+	}
+	if sc.DeployMode == DEPLOY_MODE_CLUSTER_ONLY {
+		return sc, errors.New("deploy_mode " + DEPLOY_MODE_CLUSTER_ONLY + " is not supported by ravendb_node, use ravendb_cluster instead")
+	}
+	sc.Hosts = []string{d.Get("host").(string)}
+	sc.Url.List = []string{d.Get("url").(string)}
+	sc.Url.HttpPort, sc.Url.TcpPort = parsePorts(map[string]interface{}{
+		"http_port": d.Get("http_port"),
+		"tcp_port":  d.Get("tcp_port"),
+	}, sc.Unsecured)
+	return sc, sc.resolveSetupPackage()
+}
+
+func resourceNodeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	sc, err := parseNodeData(d)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorNodeCreate, err.Error()))
+	}
+	sc.stores = meta.(*storeCache)
+	err = sc.fetchSetupPackage(d)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorNodeCreate, err.Error()))
+	}
+
+	diags := append(settingsWarnings(sc), publicUrlWarnings(sc)...)
+
+	err = sc.deployRavenDbInstances()
+	if err != nil {
+		diags = append(diags, diag.FromErr(fmt.Errorf(errorNodeCreate, err.Error()))...)
+		return append(diags, sc.rollback()...)
+	}
+	d.SetId(sc.Hosts[0])
+
+	diags = append(diags, readNodeState(d, sc)...)
+	if diags.HasError() {
+		return diags
+	}
+	err = setDeploymentReport(d, sc.report, map[string]string{sc.Hosts[0]: d.Get("version").(string)})
+	if err != nil {
+		return append(diags, diag.FromErr(fmt.Errorf(errorNodeCreate, err.Error()))...)
+	}
+	return diags
+}
+
+func resourceNodeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	sc, err := parseNodeData(d)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorNodeRead, err.Error()))
+	}
+	sc.stores = meta.(*storeCache)
+
+	return readNodeState(d, sc)
+}
+
+func readNodeState(d *schema.ResourceData, sc ServerConfig) diag.Diagnostics {
+	node, err := sc.ReadServer(sc.Hosts[0], 0)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorNodeRead, err.Error()))
+	}
+
+	var diags diag.Diagnostics
+	for _, warning := range node.Warnings {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  warning,
+		})
+	}
+
+	outdated := []string{}
+	if node.CertificateOutdated {
+		outdated = append(outdated, node.Host)
+	}
+	values := map[string]interface{}{
+		"version":               node.Version,
+		"http_url":              node.HttpUrl,
+		"tcp_url":               node.TcpUrl,
+		"settings":              node.Settings,
+		"outdated_certificates": outdated,
+	}
+	for key, value := range values {
+		err = d.Set(key, value)
+		if err != nil {
+			return append(diags, diag.FromErr(fmt.Errorf(errorNodeRead, err.Error()))...)
+		}
+	}
+	return diags
+}
+
+func resourceNodeCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	err := customizePackageVersion(d)
+	if err != nil {
+		return err
+	}
+	return customizeOutdatedCertificates(d)
+}
+
+func resourceNodeDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	sc, err := parseNodeData(d)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorNodeDelete, err.Error()))
+	}
+	sc.stores = meta.(*storeCache)
+	err = sc.purgeRavenDbInstance(sc.Hosts[0])
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorNodeDelete, err.Error()))
+	}
+	return nil
+}
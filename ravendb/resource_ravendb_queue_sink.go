@@ -0,0 +1,326 @@
+package ravendb
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/ravendb/terraform-provider-ravendb/operations"
+	"strconv"
+)
+
+const (
+	errorQueueSinkPut    = "error while configuring RavenDB queue sink: %s"
+	errorQueueSinkRead   = "error reading RavenDB queue sink: %s"
+	errorQueueSinkDelete = "error deleting RavenDB queue sink: %s"
+)
+
+const (
+	QUEUE_BROKER_KAFKA    string = "Kafka"
+	QUEUE_BROKER_RABBITMQ string = "RabbitMq"
+)
+
+func resourceRavendbQueueSink() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceQueueSinkPut,
+		ReadContext:   resourceQueueSinkRead,
+		UpdateContext: resourceQueueSinkPut,
+		DeleteContext: resourceQueueSinkDelete,
+
+		Schema: withConnectionSchema(map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"connection_string_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The name of the connection string the resource manages for the task, the task name by default.",
+			},
+			"kafka": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				MaxItems:     1,
+				ExactlyOneOf: []string{"kafka", "rabbitmq"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"bootstrap_servers": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Comma separated host:port of the brokers.",
+						},
+						"consumer_options": {
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Description: "Kafka consumer configuration, e.g. group.id or auto.offset.reset.",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"use_raven_certificate": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Authenticates to the brokers with the server certificate of the node.",
+						},
+					},
+				},
+			},
+			"rabbitmq": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"connection_string": {
+							Type:      schema.TypeString,
+							Required:  true,
+							Sensitive: true,
+						},
+					},
+				},
+			},
+			"script": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"queues": {
+							Type:        schema.TypeList,
+							Required:    true,
+							MinItems:    1,
+							Description: "The queues, or Kafka topics, consumed by the script.",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"script": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The script turning a message, this, into documents with put().",
+						},
+						"disabled": {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"disabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"mentor_node": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The tag of the node preferred to run the task.",
+			},
+			"task_id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		}),
+	}
+}
+
+func parseQueueSink(d *schema.ResourceData) (operations.QueueConnectionString, operations.QueueSinkConfiguration) {
+	connectionStringName := d.Get("connection_string_name").(string)
+	if connectionStringName == "" {
+		connectionStringName = d.Get("name").(string)
+	}
+	connectionString := operations.QueueConnectionString{
+		Name: connectionStringName,
+		Type: "Queue",
+	}
+	for _, v := range d.Get("kafka").(*schema.Set).List() {
+		value := v.(map[string]interface{})
+		options := map[string]string{}
+		for key, option := range value["consumer_options"].(map[string]interface{}) {
+			options[key] = option.(string)
+		}
+		connectionString.BrokerType = QUEUE_BROKER_KAFKA
+		connectionString.KafkaConnectionSettings = &operations.KafkaConnectionSettings{
+			BootstrapServers:    value["bootstrap_servers"].(string),
+			ConnectionOptions:   options,
+			UseRavenCertificate: value["use_raven_certificate"].(bool),
+		}
+	}
+	for _, v := range d.Get("rabbitmq").(*schema.Set).List() {
+		connectionString.BrokerType = QUEUE_BROKER_RABBITMQ
+		connectionString.RabbitMqConnectionSettings = &operations.RabbitMqConnectionSettings{
+			ConnectionString: v.(map[string]interface{})["connection_string"].(string),
+		}
+	}
+
+	configuration := operations.QueueSinkConfiguration{
+		TaskId:               int64(d.Get("task_id").(int)),
+		Name:                 d.Get("name").(string),
+		ConnectionStringName: connectionStringName,
+		BrokerType:           connectionString.BrokerType,
+		Disabled:             d.Get("disabled").(bool),
+		MentorNode:           d.Get("mentor_node").(string),
+	}
+	for _, v := range d.Get("script").([]interface{}) {
+		value := v.(map[string]interface{})
+		var queues []string
+		for _, queue := range value["queues"].([]interface{}) {
+			queues = append(queues, queue.(string))
+		}
+		configuration.Scripts = append(configuration.Scripts, operations.QueueSinkScript{
+			Name:     value["name"].(string),
+			Queues:   queues,
+			Script:   value["script"].(string),
+			Disabled: value["disabled"].(bool),
+		})
+	}
+	return connectionString, configuration
+}
+
+func resourceQueueSinkPut(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	database := d.Get("database").(string)
+	connectionString, configuration := parseQueueSink(d)
+
+	store, err := getStoreFromData(d, meta, database)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorQueueSinkPut, err.Error()))
+	}
+
+	// the task refers to the connection string by name, so it has to exist first
+	err = executeWithRetries(store, &operations.OperationPutQueueConnectionString{
+		Database:         database,
+		ConnectionString: connectionString,
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorQueueSinkPut, err.Error()))
+	}
+
+	operation := operations.OperationPutQueueSink{
+		Database:      database,
+		Configuration: configuration,
+	}
+	err = executeWithRetries(store, &operation)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorQueueSinkPut, err.Error()))
+	}
+
+	d.SetId(database + "/queue-sink/" + strconv.FormatInt(operation.Result.TaskId, 10))
+	values := map[string]interface{}{
+		"task_id":                int(operation.Result.TaskId),
+		"connection_string_name": connectionString.Name,
+	}
+	for key, value := range values {
+		err = d.Set(key, value)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf(errorQueueSinkPut, err.Error()))
+		}
+	}
+
+	return resourceQueueSinkRead(ctx, d, meta)
+}
+
+func resourceQueueSinkRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	database := d.Get("database").(string)
+	store, err := getStoreFromData(d, meta, database)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorQueueSinkRead, err.Error()))
+	}
+
+	record := operations.OperationGetDatabaseRecord{Database: database}
+	err = executeWithRetries(store, &record)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorQueueSinkRead, err.Error()))
+	}
+	sinks, err := record.Result.QueueSinks()
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorQueueSinkRead, err.Error()))
+	}
+
+	var sink *operations.QueueSinkConfiguration
+	for i := range sinks {
+		if sinks[i].TaskId == int64(d.Get("task_id").(int)) {
+			sink = &sinks[i]
+		}
+	}
+	if sink == nil {
+		d.SetId("")
+		return nil
+	}
+
+	var scripts []interface{}
+	for _, script := range sink.Scripts {
+		scripts = append(scripts, map[string]interface{}{
+			"name":     script.Name,
+			"queues":   script.Queues,
+			"script":   script.Script,
+			"disabled": script.Disabled,
+		})
+	}
+	values := map[string]interface{}{
+		"name":                   sink.Name,
+		"connection_string_name": sink.ConnectionStringName,
+		"disabled":               sink.Disabled,
+		"mentor_node":            sink.MentorNode,
+		"script":                 scripts,
+	}
+
+	connectionStrings, err := record.Result.QueueConnectionStrings()
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorQueueSinkRead, err.Error()))
+	}
+	// the RabbitMQ connection string holds credentials, it is kept from the resource data
+	if connectionString, ok := connectionStrings[sink.ConnectionStringName]; ok && connectionString.KafkaConnectionSettings != nil {
+		kafka := connectionString.KafkaConnectionSettings
+		values["kafka"] = []interface{}{map[string]interface{}{
+			"bootstrap_servers":     kafka.BootstrapServers,
+			"consumer_options":      kafka.ConnectionOptions,
+			"use_raven_certificate": kafka.UseRavenCertificate,
+		}}
+	}
+
+	for key, value := range values {
+		err = d.Set(key, value)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf(errorQueueSinkRead, err.Error()))
+		}
+	}
+
+	return nil
+}
+
+func resourceQueueSinkDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	database := d.Get("database").(string)
+	store, err := getStoreFromData(d, meta, database)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorQueueSinkDelete, err.Error()))
+	}
+
+	err = executeWithRetries(store, &operations.OperationDeleteOngoingTask{
+		Database: database,
+		TaskId:   int64(d.Get("task_id").(int)),
+		TaskType: "QueueSink",
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorQueueSinkDelete, err.Error()))
+	}
+
+	err = executeWithRetries(store, &operations.OperationRemoveConnectionString{
+		Database: database,
+		Name:     d.Get("connection_string_name").(string),
+		Type:     "Queue",
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorQueueSinkDelete, err.Error()))
+	}
+	return nil
+}
@@ -9,7 +9,9 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"net"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"sync"
@@ -19,8 +21,21 @@ const (
 	errorCreate = "error while creating RavenDB instances: %s"
 	errorRead   = "error reading RavenDB configuration information: %s"
 	errorDelete = "error deleting RavenDB instances: %s"
+	errorUpdate = "error updating RavenDB instances: %s"
 )
 
+// managedSettings are the settings.json keys deployServer derives from the resource configuration.
+var managedSettings = []string{
+	"serverurl",
+	"serverurl.tcp",
+	"publicserverurl",
+	"publicserverurl.tcp",
+	"setup.mode",
+	"license.path",
+	"security.certificate.path",
+	"security.unsecuredaccessallowed",
+}
+
 var packageArchitectures = map[string]string{
 	"arm64": "_linux-arm64",
 	"arm32": "-0_armhf.deb",
@@ -33,68 +48,43 @@ func resourceRavendbServer() *schema.Resource {
 		ReadContext:   resourceServerRead,
 		UpdateContext: resourceServerUpdate,
 		DeleteContext: resourceServerDelete,
+		CustomizeDiff: resourceServerCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceServerImport,
+		},
 
-		Schema: map[string]*schema.Schema{
+		Schema: withNodeSchema(map[string]*schema.Schema{
 			"hosts": {
 				Type:        schema.TypeList,
-				Required:    true,
+				Optional:    true,
 				Description: "The hostnames (or ip addresses) of the nodes that terraform will use to setup the RavenDB cluster.",
 				Elem: &schema.Schema{
 					Type:         schema.TypeString,
-					ValidateFunc: validation.IsIPAddress,
+					ValidateFunc: validateHost,
 				},
 				MinItems: 1,
 			},
+			"quickstart": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Description:   "Deploys an unsecured cluster from hosts, package and license alone, with the node urls derived from the hosts. For ephemeral CI and development environments only.",
+				ConflictsWith: []string{"certificate", "setup_package", "node"},
+			},
 			"database": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Description: "The database name to check whether he is alive or not.",
 			},
-			"certificate": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				Description:  "The cluster certificate file that is used by RavenDB for server side authentication.",
-				ValidateFunc: validation.StringIsBase64,
-			},
-			"license": {
-				Type:         schema.TypeString,
-				Required:     true,
-				Description:  "The license that will be used for the setup of the RavenDB cluster.",
-				ValidateFunc: validation.StringIsBase64,
-			},
-			"package": {
-				Type:     schema.TypeSet,
-				Required: true,
-				MaxItems: 1,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"version": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "The RavenDB version to use for the cluster.",
-						},
-						"arch": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "Operating system architecture name - amd64, arm64, arm32",
-						},
-					},
-				},
-			},
-			"unsecured": {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Description: "Whatever to allow to run RavenDB in unsecured mode. This is ***NOT*** recommended!",
-			},
+			"node": nodeBlockSchema(),
 			"url": {
 				Type:     schema.TypeSet,
-				Required: true,
+				Optional: true,
 				MaxItems: 1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"list": {
 							Type:     schema.TypeList,
-							Required: true,
+							Optional: true,
 							Elem: &schema.Schema{
 								Type:         schema.TypeString,
 								ValidateFunc: validation.IsURLWithHTTPorHTTPS,
@@ -114,39 +104,56 @@ func resourceRavendbServer() *schema.Resource {
 					},
 				},
 			},
-			"settings_override": {
-				Type:     schema.TypeMap,
-				Optional: true,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
-				},
-			},
-			"assets": {
-				Type:     schema.TypeMap,
-				Optional: true,
-				Elem: &schema.Schema{
-					Type:         schema.TypeString,
-					ValidateFunc: validation.StringIsBase64,
-				},
-			},
-			"ssh": {
-				Type:     schema.TypeSet,
-				Required: true,
-				MaxItems: 1,
+			"parallel": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Controls which deploy phases run on all the nodes at once. Phases that are not parallel run one node after the other.",
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
-						"user": {
-							Type:     schema.TypeString,
-							Required: true,
+						"install": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
 						},
-						"pem": {
-							Type:         schema.TypeString,
-							Required:     true,
-							ValidateFunc: validation.StringIsBase64,
+						"configure": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+						"cluster_join": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
 						},
 					},
 				},
 			},
+			"readiness":            readinessSchema(),
+			"peer_hosts":           peerHostsSchema(),
+			"postgresql":           postgreSqlSchema(),
+			"consul":               consulSchema(),
+			"webhook":              webhookSchema(),
+			"client_certificates":  clientCertificatesSchema(),
+			"databases":            databasesSchema(),
+			"index_swap_status":    indexSwapStatusSchema(),
+			"healthcheck_database": healthcheckDatabaseSchema(),
+			"deployment_report":    deploymentReportSchema(),
+			"dns_records": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "The hostname of every node url mapped to the host it is deployed to, for feeding DNS modules.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"ignore_embedded_databases": ignoreEmbeddedDatabasesSchema(),
+			"hard_delete_databases":     hardDeleteDatabasesSchema(),
+			"cluster_topology": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The cluster topology as JSON: topology_id, leader and nodes, each with its tag, url and role (Member, Promotable or Watcher).",
+			},
 			"nodes": {
 				Type:        schema.TypeList,
 				Computed:    true,
@@ -199,10 +206,32 @@ func resourceRavendbServer() *schema.Resource {
 							Type:     schema.TypeBool,
 							Computed: true,
 						},
+						"service_status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The state of the ravendb service, as reported by systemctl is-active.",
+						},
+						"data_disk_used_mb": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"data_disk_free_mb": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The free space of the file system of the data directory.",
+						},
+						"memory_total_mb": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"memory_available_mb": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
 					},
 				},
 			},
-		},
+		}),
 	}
 }
 
@@ -212,13 +241,43 @@ func resourceServerCreate(ctx context.Context, d *schema.ResourceData, meta inte
 		return diag.FromErr(fmt.Errorf(errorCreate, err.Error()))
 	}
 
-	id, err := sc.Deploy(true)
+	sc.stores = meta.(*storeCache)
+	err = sc.fetchSetupPackage(d)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf(errorCreate, err.Error()))
 	}
+
+	diags := append(settingsWarnings(sc), publicUrlWarnings(sc)...)
+
+	id, err := sc.Deploy()
+	if err != nil {
+		diags = append(diags, diag.FromErr(fmt.Errorf(errorCreate, err.Error()))...)
+		return append(diags, sc.rollback()...)
+	}
 	d.SetId(id)
 
-	return resourceServerRead(ctx, d, meta)
+	diags = append(diags, readServerState(d, sc)...)
+	if diags.HasError() {
+		return diags
+	}
+	err = reportServerDeployment(d, sc)
+	if err != nil {
+		return append(diags, diag.FromErr(fmt.Errorf(errorCreate, err.Error()))...)
+	}
+	return append(diags, sc.Webhook.notify(d, sc, WEBHOOK_ACTION_CREATE)...)
+}
+
+// reportServerDeployment sets the deployment_report of the deploy that just ran, with the versions read back
+// from the nodes.
+func reportServerDeployment(d *schema.ResourceData, sc ServerConfig) error {
+	versions := make(map[string]string)
+	for _, node := range d.Get("nodes").([]interface{}) {
+		if node != nil {
+			values := node.(map[string]interface{})
+			versions[values["host"].(string)] = values["version"].(string)
+		}
+	}
+	return setDeploymentReport(d, sc.report, versions)
 }
 
 func resourceServerDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -226,127 +285,116 @@ func resourceServerDelete(ctx context.Context, d *schema.ResourceData, meta inte
 	if err != nil {
 		return diag.FromErr(fmt.Errorf(errorDelete, err.Error()))
 	}
-	return sc.RemoveRavenDbInstances()
+	sc.stores = meta.(*storeCache)
+	diags := sc.RemoveRavenDbInstances()
+	if diags.HasError() {
+		return diags
+	}
+	return append(diags, sc.Webhook.notify(d, sc, WEBHOOK_ACTION_DESTROY)...)
 }
 
 func convertNode(node NodeState) map[string]interface{} {
 	return map[string]interface{}{
-		"host":        node.Host,
-		"license":     base64.StdEncoding.EncodeToString(node.Licence),
-		"settings":    node.Settings,
-		"certificate": base64.StdEncoding.EncodeToString(node.ClusterCertificate),
-		"http_url":    node.HttpUrl,
-		"tcp_url":     node.TcpUrl,
-		"assets":      node.Assets,
-		"unsecured":   node.Unsecured,
-		"version":     node.Version,
-		"failed":      node.Failed,
+		"host":                node.Host,
+		"license":             base64.StdEncoding.EncodeToString(node.Licence),
+		"settings":            node.Settings,
+		"certificate":         base64.StdEncoding.EncodeToString(node.ClusterCertificate),
+		"http_url":            node.HttpUrl,
+		"tcp_url":             node.TcpUrl,
+		"assets":              node.Assets,
+		"unsecured":           node.Unsecured,
+		"version":             node.Version,
+		"failed":              node.Failed,
+		"service_status":      node.ServiceStatus,
+		"data_disk_used_mb":   node.DataDiskUsedMb,
+		"data_disk_free_mb":   node.DataDiskFreeMb,
+		"memory_total_mb":     node.MemoryTotalMb,
+		"memory_available_mb": node.MemoryAvailableMb,
 	}
 }
 
 func parseData(d *schema.ResourceData) (ServerConfig, error) {
-	var sc ServerConfig
-
-	if unsecured, ok := d.GetOk("unsecured"); ok {
-		sc.Unsecured = unsecured.(bool)
-	}
-
-	hosts := d.Get("hosts").([]interface{})
-	sc.Hosts = make([]string, len(hosts))
-	for i, host := range hosts {
-		sc.Hosts[i] = host.(string)
-	}
-
-	if dbName, ok := d.GetOk("database"); ok {
-		sc.HealthcheckDatabase = dbName.(string)
+	sc, err := parseNodeConfig(d)
+	if err != nil {
+		return sc, err
 	}
 
-	certBas64 := d.Get("certificate").(string)
-	cert, err := base64.StdEncoding.DecodeString(certBas64)
+	sc.Hosts, sc.Url.List, err = nodeLists(d.Get)
 	if err != nil {
 		return sc, err
 	}
-	if allZero(cert) == false {
-		sc.ClusterCertificate = cert
+	if sc.Connection == CONNECTION_LOCAL && len(sc.Hosts) > 1 {
+		return sc, errors.New("connection " + CONNECTION_LOCAL + " supports a single host, got " + strconv.Itoa(len(sc.Hosts)))
 	}
 
-	licenseBas64 := d.Get("license").(string)
-	license, err := base64.StdEncoding.DecodeString(licenseBas64)
-	if err != nil {
-		return sc, err
+	sc.Healthcheck = parseHealthcheckDatabase(d)
+	if dbName, ok := d.GetOk("database"); ok {
+		sc.HealthcheckDatabase = sc.Healthcheck.NamePrefix + dbName.(string)
 	}
-	sc.License = license
 
-	packageSet := d.Get("package").(*schema.Set).List()
-	for _, v := range packageSet {
+	sc.Parallel = Parallel{Install: true, Configure: true}
+	for _, v := range d.Get("parallel").(*schema.Set).List() {
 		value := v.(map[string]interface{})
-		sc.Package.Version = value["version"].(string)
-		sc.Package.Arch = value["arch"].(string)
-		err := validatePackage(&sc)
-		if err != nil {
-			return sc, err
+		sc.Parallel = Parallel{
+			Install:     value["install"].(bool),
+			Configure:   value["configure"].(bool),
+			ClusterJoin: value["cluster_join"].(bool),
 		}
 	}
 
-	assets := d.Get("assets").(map[string]interface{})
-	sc.Assets = map[string][]byte{}
-	for name, base64Val := range assets {
-		value, err := base64.StdEncoding.DecodeString(base64Val.(string))
-		if err != nil {
-			return sc, err
-		}
-		sc.Assets[name] = value
+	sc.Readiness = parseReadiness(d)
+	sc.PeerHosts = parsePeerHosts(d)
+	sc.PostgreSql = parsePostgreSql(d)
+	sc.Consul = parseConsul(d)
+	sc.Webhook = parseWebhook(d)
+	sc.Databases = parseDatabases(d)
+	sc.ClientCertificates, err = parseClientCertificates(d)
+	if err != nil {
+		return sc, err
+	}
+
+	for _, v := range d.Get("url").(*schema.Set).List() {
+		sc.Url.HttpPort, sc.Url.TcpPort = parsePorts(v.(map[string]interface{}), sc.Unsecured)
 	}
-	settings := d.Get("settings_override").(map[string]interface{})
-	sc.Settings = make(map[string]interface{})
-	for k, v := range settings {
-		sc.Settings[k] = v.(string)
+	err = sc.parseNodeBlocks(d)
+	if err != nil {
+		return sc, err
 	}
 
-	sshSet := d.Get("ssh").(*schema.Set).List()
-	for _, v := range sshSet {
-		value := v.(map[string]interface{})
-		sc.SSH.User = value["user"].(string)
-		pemBase64 := value["pem"].(string)
-		pem, err := base64.StdEncoding.DecodeString(pemBase64)
-		if err != nil {
-			return sc, err
-		}
-		sc.SSH.Pem = pem
+	err = sc.resolveSetupPackage()
+	if err != nil {
+		return sc, err
+	}
+	if sc.SetupPackage != nil && sc.SetupPackage.Archive == nil && !d.HasChange("setup_package") {
+		// applied before the created package was kept, the nodes hold what it was set up with
+		sc.ClusterCertificate, sc.License = setupFromNodes(d, sc.License)
 	}
+	return sc, nil
+}
 
-	urlSet := d.Get("url").(*schema.Set).List()
-	for _, v := range urlSet {
-		value := v.(map[string]interface{})
-		list := value["list"].([]interface{})
-		sc.Url.List = make([]string, len(list))
-		for i, url := range list {
-			sc.Url.List[i] = url.(string)
+// setupFromNodes returns the cluster certificate and the license the nodes were last read with. The given
+// license is kept when set.
+func setupFromNodes(d *schema.ResourceData, license []byte) ([]byte, []byte) {
+	var certificate []byte
+	for _, node := range d.Get("nodes").([]interface{}) {
+		if node == nil {
+			continue
 		}
-		if httpPort, ok := value["http_port"]; ok {
-			sc.Url.HttpPort = httpPort.(int)
-		} else {
-			sc.Url.HttpPort = DEFAULT_SECURE_RAVENDB_HTTP_PORT
-			if sc.Unsecured {
-				sc.Url.HttpPort = DEFAULT_USECURED_RAVENDB_HTTP_PORT
-			}
+		values := node.(map[string]interface{})
+		if certificate == nil {
+			certificate, _ = base64.StdEncoding.DecodeString(values["certificate"].(string))
 		}
-
-		if tcpPort, ok := value["tcp_port"]; ok {
-			sc.Url.TcpPort = tcpPort.(int)
-		} else {
-			sc.Url.TcpPort = DEFAULT_SECURE_RAVENDB_TCP_PORT
-			if sc.Unsecured {
-				sc.Url.TcpPort = DEFAULT_UNSECURED_RAVENDB_TCP_PORT
-			}
+		if license == nil {
+			license, _ = base64.StdEncoding.DecodeString(values["license"].(string))
 		}
 	}
-
-	if sc.ClusterCertificate != nil && sc.Unsecured == true {
-		return sc, fmt.Errorf("expected unsecure to be ture. certificate should be added when using secure mode")
+	if len(certificate) == 0 {
+		certificate = nil
 	}
-
-	return sc, nil
+	if len(license) == 0 {
+		license = nil
+	}
+	return certificate, license
 }
 func allZero(s []byte) bool {
 	for _, v := range s {
@@ -357,52 +405,91 @@ func allZero(s []byte) bool {
 	return true
 }
 func validatePackage(sc *ServerConfig) error {
-	arc := strings.ToLower(sc.Package.Arch)
-	if val, ok := packageArchitectures[arc]; ok {
-		sc.Package.Arch = val
-	}
-	if len(strings.TrimSpace(sc.Package.Arch)) == 0 {
-		sc.Package.Arch = "-0_amd64.deb"
+	sc.Package.Arch = packageArch(sc.Package.Arch)
+	return sc.Package.checkUrl()
+}
+
+// checkUrl checks that the Debian package of p can be downloaded, unless SkipUrlCheck is set.
+func (p Package) checkUrl() error {
+	if p.SkipUrlCheck {
+		return nil
 	}
-	link := "https://daily-builds.s3.us-east-1.amazonaws.com/ravendb_" + sc.Package.Version + sc.Package.Arch
+	link := p.debianUrl()
 	response, err := http.Head(link)
 	if err != nil {
-		return errors.New("unable to download the RavenDB version: " + sc.Package.Version + ", from: " + link + " because of:" + "err")
+		return errors.New("unable to download the RavenDB version: " + p.Version + ", from: " + link + " because of:" + "err")
 	} else if response.StatusCode != http.StatusOK {
-		return errors.New(link + " is not reachable. HTTP status code: " + strconv.Itoa(response.StatusCode) + ". Please check the input version:" + sc.Package.Version + ". Url used was:" + link)
+		return errors.New(link + " is not reachable. HTTP status code: " + strconv.Itoa(response.StatusCode) + ". Please check the input version:" + p.Version + ". Url used was:" + link)
 	}
 	return nil
 }
 
 func resourceServerRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if isImportedServer(d) {
+		err := readImportedServerState(d, meta.(*storeCache))
+		if err != nil {
+			return diag.FromErr(fmt.Errorf(errorRead, err.Error()))
+		}
+		return nil
+	}
 	sc, err := parseData(d)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf(errorRead, err.Error()))
 	}
+	sc.stores = meta.(*storeCache)
 
-	nodes, err := readRavenDbInstances(sc)
-	if err != nil {
-		return diag.FromErr(fmt.Errorf(errorRead, err.Error()))
+	return readServerState(d, sc)
+}
+
+func readServerState(d *schema.ResourceData, sc ServerConfig) diag.Diagnostics {
+	nodes, diags := readRavenDbInstances(sc)
+	if diags.HasError() {
+		return diags
 	}
 
 	convertedNodes := make([]interface{}, len(nodes))
+	outdated := []string{}
 	for index, node := range nodes {
 		if node.Failed == false {
 			convertedNodes[index] = convertNode(node)
 		}
+		if node.CertificateOutdated {
+			outdated = append(outdated, node.Host)
+		}
 	}
 
-	err = d.Set("nodes", convertedNodes)
+	err := d.Set("nodes", convertedNodes)
 	if err != nil {
-		return diag.FromErr(fmt.Errorf(errorRead, err.Error()))
+		return append(diags, diag.FromErr(fmt.Errorf(errorRead, err.Error()))...)
+	}
+	err = d.Set("outdated_certificates", outdated)
+	if err != nil {
+		return append(diags, diag.FromErr(fmt.Errorf(errorRead, err.Error()))...)
 	}
 
-	return nil
+	err = d.Set("dns_records", dnsRecords(sc.Hosts, sc.Url.List))
+	if err != nil {
+		return append(diags, diag.FromErr(fmt.Errorf(errorRead, err.Error()))...)
+	}
+
+	err = sc.setIndexSwapStatus(d)
+	if err != nil {
+		return append(diags, diag.FromErr(fmt.Errorf(errorRead, err.Error()))...)
+	}
+
+	err = sc.setClusterTopology(d)
+	if err != nil {
+		return append(diags, diag.FromErr(fmt.Errorf(errorRead, err.Error()))...)
+	}
+
+	return diags
 }
 
-func readRavenDbInstances(sc ServerConfig) ([]NodeState, error) {
+func readRavenDbInstances(sc ServerConfig) ([]NodeState, diag.Diagnostics) {
 	var wg sync.WaitGroup
 	var errResults error
+	var diags diag.Diagnostics
+	var mu sync.Mutex
 	errorsChanel := make(chan error, len(sc.Hosts))
 
 	nodeStateArray := make([]NodeState, len(sc.Hosts))
@@ -410,17 +497,31 @@ func readRavenDbInstances(sc ServerConfig) ([]NodeState, error) {
 	for index, publicIp := range sc.Hosts {
 		wg.Add(1)
 		go func(copyOfPublicIp string, copyOfIndex int) {
+			defer wg.Done()
 			nodeState, err := sc.ReadServer(copyOfPublicIp, copyOfIndex)
 			if err != nil {
 				if strings.Contains(err.Error(), "Unable to SSH to") {
-					nodeState.Failed = true
-					wg.Done()
-					return
+					mu.Lock()
+					diags = append(diags, diag.Diagnostic{
+						Severity: diag.Warning,
+						Summary:  "RavenDB node " + copyOfPublicIp + " is unreachable",
+						Detail:   "The node state was not refreshed and is reported as failed: " + err.Error(),
+					})
+					mu.Unlock()
+					nodeStateArray[copyOfIndex] = NodeState{Host: copyOfPublicIp, Failed: true}
 				} else {
 					errorsChanel <- err
 				}
+				return
 			}
-			wg.Done()
+			mu.Lock()
+			for _, warning := range nodeState.Warnings {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  warning,
+				})
+			}
+			mu.Unlock()
 			nodeStateArray[copyOfIndex] = nodeState
 		}(publicIp, index)
 	}
@@ -432,12 +533,154 @@ func readRavenDbInstances(sc ServerConfig) ([]NodeState, error) {
 		for err := range errorsChanel {
 			errResults = multierror.Append(errResults, err)
 		}
-		return nil, errResults
+		return nil, append(diags, diag.FromErr(fmt.Errorf(errorRead, errResults.Error()))...)
+	}
+
+	return nodeStateArray, diags
+}
+
+// settingsWarnings flags settings_override keys that replace values the provider computes
+// itself, which is allowed but usually leaves the node unreachable through the configured urls,
+// and keys that look like a typo of a known setting, which the server would silently ignore. Other
+// unknown keys aren't flagged, as they may be settings of a RavenDB version the list doesn't cover.
+func settingsWarnings(sc ServerConfig) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for key := range sc.Settings {
+		if contains(managedSettings, strings.ToLower(key)) {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "settings_override overrides the provider managed setting " + key,
+				Detail:   "The value is written as-is and replaces the value derived from the resource configuration.",
+			})
+		} else if suggestion := similarSetting(key); suggestion != "" {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "settings_override sets " + key + ", which looks like a mistyped RavenDB server setting",
+				Detail:   "Did you mean " + suggestion + "? RavenDB ignores settings it doesn't know.",
+			})
+		}
 	}
+	return diags
+}
 
-	return nodeStateArray, nil
+// publicUrlWarnings flags node urls whose hostname doesn't resolve to the host the node is deployed to, which
+// otherwise only surfaces once the packages are installed and the node can't be reached.
+func publicUrlWarnings(sc ServerConfig) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for index, nodeUrl := range sc.Url.List {
+		if index >= len(sc.Hosts) {
+			break
+		}
+		u, err := url.Parse(nodeUrl)
+		if err != nil || net.ParseIP(u.Hostname()) != nil {
+			continue
+		}
+		if u.Hostname() == sc.Hosts[index] {
+			continue
+		}
+		addresses, err := net.LookupHost(u.Hostname())
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "The hostname of " + nodeUrl + " does not resolve",
+				Detail:   err.Error(),
+			})
+			continue
+		}
+		hostIps, err := hostAddresses(sc.Hosts[index])
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "The host " + sc.Hosts[index] + " does not resolve",
+				Detail:   err.Error(),
+			})
+		} else if !containsAny(addresses, hostIps) {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "The hostname of " + nodeUrl + " does not resolve to its host " + sc.Hosts[index],
+				Detail:   "It resolves to " + strings.Join(addresses, ", ") + ". This is expected behind NAT or a load balancer, otherwise the node won't be reachable through its url.",
+			})
+		}
+	}
+	return diags
 }
 
 func resourceServerUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	return resourceServerCreate(ctx, d, meta)
+	sc, err := parseData(d)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorUpdate, err.Error()))
+	}
+	sc.stores = meta.(*storeCache)
+	err = sc.fetchSetupPackage(d)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorUpdate, err.Error()))
+	}
+
+	diags := append(settingsWarnings(sc), publicUrlWarnings(sc)...)
+
+	err = sc.update(d)
+	if err != nil {
+		diags = append(diags, diag.FromErr(fmt.Errorf(errorUpdate, err.Error()))...)
+		return append(diags, sc.rollback()...)
+	}
+
+	diags = append(diags, readServerState(d, sc)...)
+	if diags.HasError() {
+		return diags
+	}
+	err = reportServerDeployment(d, sc)
+	if err != nil {
+		return append(diags, diag.FromErr(fmt.Errorf(errorUpdate, err.Error()))...)
+	}
+	err = sc.deleteRemovedDatabases(d)
+	if err != nil {
+		return append(diags, diag.FromErr(fmt.Errorf(errorUpdate, err.Error()))...)
+	}
+	err = sc.deregisterRemovedServices(d)
+	if err != nil {
+		return append(diags, diag.FromErr(fmt.Errorf(errorUpdate, err.Error()))...)
+	}
+	return append(diags, sc.Webhook.notify(d, sc, WEBHOOK_ACTION_UPDATE)...)
+}
+
+func resourceServerCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	err := customizePackageVersion(d)
+	if err != nil {
+		return err
+	}
+	err = customizeOutdatedCertificates(d)
+	if err != nil {
+		return err
+	}
+	err = customizeHealthcheckDatabase(ctx, d, meta)
+	if err != nil {
+		return err
+	}
+	if d.NewValueKnown("hosts") == false || d.NewValueKnown("url") == false || d.NewValueKnown("node") == false {
+		return nil
+	}
+
+	hostList, urlList, err := nodeLists(d.Get)
+	if err != nil {
+		return err
+	}
+
+	return d.SetNew("dns_records", dnsRecords(hostList, urlList))
+}
+
+// dnsRecords maps the hostname of every node url to the matching host. Urls that already point at an ip address or at
+// their host are skipped.
+func dnsRecords(hosts []string, urls []string) map[string]string {
+	records := map[string]string{}
+	for index, nodeUrl := range urls {
+		if index >= len(hosts) {
+			break
+		}
+		u, err := url.Parse(nodeUrl)
+		if err != nil || net.ParseIP(u.Hostname()) != nil || u.Hostname() == hosts[index] {
+			continue
+		}
+		records[u.Hostname()] = hosts[index]
+	}
+	return records
 }
@@ -1,7 +1,7 @@
 package ravendb
 
 import (
-	internal_operations "../operations"
+	internal_operations "github.com/ravendb/terraform-provider-ravendb/operations"
 	"errors"
 	"fmt"
 	"github.com/gruntwork-io/terratest/modules/terraform"
@@ -0,0 +1,156 @@
+package ravendb
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/ravendb/terraform-provider-ravendb/operations"
+)
+
+const (
+	errorServerWideReplicationPut    = "error while configuring RavenDB server-wide external replication: %s"
+	errorServerWideReplicationRead   = "error reading RavenDB server-wide external replication: %s"
+	errorServerWideReplicationDelete = "error deleting RavenDB server-wide external replication: %s"
+)
+
+func resourceRavendbServerWideReplication() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceServerWideReplicationPut,
+		ReadContext:   resourceServerWideReplicationRead,
+		UpdateContext: resourceServerWideReplicationPut,
+		DeleteContext: resourceServerWideReplicationDelete,
+
+		Schema: withConnectionSchema(map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"topology_discovery_urls": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "The urls of the destination cluster. Every database replicates to the database of the same name on it.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				},
+			},
+			"excluded_databases": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "The databases that are not replicated.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"disabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"mentor_node": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The tag of the node preferred to run the replication.",
+			},
+			"delay_replication_sec": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Delays the replication of every change, to keep a delayed copy of the databases.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+		}),
+	}
+}
+
+func resourceServerWideReplicationPut(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	store, err := getStoreFromData(d, meta, "")
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorServerWideReplicationPut, err.Error()))
+	}
+
+	configuration := operations.ServerWideExternalReplication{
+		Name:                  d.Get("name").(string),
+		Disabled:              d.Get("disabled").(bool),
+		MentorNode:            d.Get("mentor_node").(string),
+		TopologyDiscoveryUrls: []string{},
+		ExcludedDatabases:     []string{},
+	}
+	for _, u := range d.Get("topology_discovery_urls").([]interface{}) {
+		configuration.TopologyDiscoveryUrls = append(configuration.TopologyDiscoveryUrls, u.(string))
+	}
+	for _, database := range d.Get("excluded_databases").(*schema.Set).List() {
+		configuration.ExcludedDatabases = append(configuration.ExcludedDatabases, database.(string))
+	}
+	if delay := d.Get("delay_replication_sec").(int); delay > 0 {
+		configuration.DelayReplicationFor = formatTimeSpan(delay)
+	}
+
+	operation := operations.OperationPutServerWideExternalReplication{Configuration: configuration}
+	err = executeWithRetries(store, &operation)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorServerWideReplicationPut, err.Error()))
+	}
+	d.SetId("server-wide/replication/" + operation.Result.Name)
+
+	return resourceServerWideReplicationRead(ctx, d, meta)
+}
+
+func resourceServerWideReplicationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	store, err := getStoreFromData(d, meta, "")
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorServerWideReplicationRead, err.Error()))
+	}
+
+	operation := operations.OperationGetServerWideExternalReplication{Name: d.Get("name").(string)}
+	err = executeWithRetries(store, &operation)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorServerWideReplicationRead, err.Error()))
+	}
+	replication := operation.Result
+	if replication == nil {
+		d.SetId("")
+		return nil
+	}
+
+	delay := 0
+	if replication.DelayReplicationFor != "" {
+		delay, err = parseTimeSpan(replication.DelayReplicationFor)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf(errorServerWideReplicationRead, err.Error()))
+		}
+	}
+	values := map[string]interface{}{
+		"topology_discovery_urls": replication.TopologyDiscoveryUrls,
+		"excluded_databases":      replication.ExcludedDatabases,
+		"disabled":                replication.Disabled,
+		"mentor_node":             replication.MentorNode,
+		"delay_replication_sec":   delay,
+	}
+	for key, value := range values {
+		err = d.Set(key, value)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf(errorServerWideReplicationRead, err.Error()))
+		}
+	}
+
+	return nil
+}
+
+func resourceServerWideReplicationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	store, err := getStoreFromData(d, meta, "")
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorServerWideReplicationDelete, err.Error()))
+	}
+
+	err = executeWithRetries(store, &operations.OperationDeleteServerWideTask{
+		Name:     d.Get("name").(string),
+		TaskType: "Replication",
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorServerWideReplicationDelete, err.Error()))
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package ravendb
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/ravendb/ravendb-go-client"
+	"github.com/ravendb/terraform-provider-ravendb/operations"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	errorSmugglerImport = "error while importing into RavenDB database: %s"
+	errorSmugglerExport = "error while exporting RavenDB database: %s"
+)
+
+var smugglerTypes = []string{"DatabaseRecord", "Documents", "RevisionDocuments", "Indexes", "Identities", "CompareExchange", "Counters", "Attachments", "Subscriptions", "TimeSeries"}
+
+func resourceRavendbSmuggler() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceSmugglerCreate,
+		ReadContext:   schema.NoopContext,
+		UpdateContext: schema.NoopContext,
+		DeleteContext: resourceSmugglerDelete,
+
+		Schema: withConnectionSchema(map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"import_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The local path of a .ravendbdump file imported into the database on create.",
+			},
+			"export_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The local path the database is exported to, as a .ravendbdump file, on destroy.",
+			},
+			"operate_on_types": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "The kinds of items imported and exported. The server defaults are used when omitted.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(smugglerTypes, false),
+				},
+			},
+			"timeout_minutes": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  60,
+			},
+		}),
+	}
+}
+
+func smugglerOptions(d *schema.ResourceData) map[string]interface{} {
+	options := map[string]interface{}{}
+	var types []string
+	for _, t := range d.Get("operate_on_types").(*schema.Set).List() {
+		types = append(types, t.(string))
+	}
+	if len(types) > 0 {
+		options["OperateOnTypes"] = strings.Join(types, ",")
+	}
+	return options
+}
+
+func resourceSmugglerCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	database := d.Get("database").(string)
+
+	if path, ok := d.GetOk("import_file"); ok {
+		store, err := getStoreFromData(d, meta, database)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf(errorSmugglerImport, err.Error()))
+		}
+
+		operationId := operations.OperationGetNextOperationId{Database: database}
+		err = executeWithRetries(store, &operationId)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf(errorSmugglerImport, err.Error()))
+		}
+
+		err = executeWithRetries(store, &operations.OperationImport{
+			Database:    database,
+			OperationId: operationId.Result.Id,
+			Path:        path.(string),
+			Options:     smugglerOptions(d),
+		})
+		if err != nil {
+			return diag.FromErr(fmt.Errorf(errorSmugglerImport, err.Error()))
+		}
+
+		timeout := time.Duration(d.Get("timeout_minutes").(int)) * time.Minute
+		_, err = waitForOperation(store, database, operationId.Result.Id, timeout)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf(errorSmugglerImport, err.Error()))
+		}
+	}
+
+	d.SetId(database + "/smuggler")
+	return nil
+}
+
+func resourceSmugglerDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	path, ok := d.GetOk("export_file")
+	if !ok {
+		return nil
+	}
+	database := d.Get("database").(string)
+	store, err := getStoreFromData(d, meta, database)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorSmugglerExport, err.Error()))
+	}
+
+	err = exportToFile(store, database, smugglerOptions(d), path.(string))
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorSmugglerExport, err.Error()))
+	}
+	return nil
+}
+
+// exportToFile exports database to target, retrying like executeWithRetries. Every attempt gets its own operation
+// id and writes to its own temporary file next to target, renamed to target once the export completed, so a failed
+// attempt never leaves a partial dump behind or in front of the next one.
+func exportToFile(store *ravendb.DocumentStore, database string, options map[string]interface{}, target string) error {
+	var err error
+	for i := 0; i < NUMBER_OF_RETRIES; i++ {
+		err = exportAttempt(store, database, options, target)
+		if err == nil || !isRetryableError(err) {
+			return err
+		}
+		if i < NUMBER_OF_RETRIES-1 {
+			time.Sleep(backoff(i))
+		}
+	}
+	return err
+}
+
+func exportAttempt(store *ravendb.DocumentStore, database string, options map[string]interface{}, target string) error {
+	operationId := operations.OperationGetNextOperationId{Database: database}
+	err := executeWithRetries(store, &operationId)
+	if err != nil {
+		return err
+	}
+
+	partial, err := ioutil.TempFile(filepath.Dir(target), filepath.Base(target)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(partial.Name())
+	err = store.Maintenance().Server().Send(&operations.OperationExport{
+		Database:    database,
+		OperationId: operationId.Result.Id,
+		Options:     options,
+		Output:      partial,
+	})
+	if closeErr := partial.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(partial.Name(), target)
+}
@@ -0,0 +1,132 @@
+package ravendb
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/ravendb/terraform-provider-ravendb/operations"
+)
+
+const (
+	errorStudioPut    = "error while configuring RavenDB studio: %s"
+	errorStudioRead   = "error reading RavenDB studio configuration: %s"
+	errorStudioDelete = "error resetting RavenDB studio configuration: %s"
+)
+
+func resourceRavendbStudioConfiguration() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceStudioConfigurationPut,
+		ReadContext:   resourceStudioConfigurationRead,
+		UpdateContext: resourceStudioConfigurationPut,
+		DeleteContext: resourceStudioConfigurationDelete,
+
+		Schema: withConnectionSchema(map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The database to configure the studio for. The server wide configuration is managed when omitted.",
+			},
+			"environment": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "None",
+				Description:  "The environment tag shown in the studio banner - None, Development, Testing, Production.",
+				ValidateFunc: validation.StringInSlice([]string{"None", "Development", "Testing", "Production"}, false),
+			},
+			"disabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"disable_auto_index_creation": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+		}),
+	}
+}
+
+func resourceStudioConfigurationPut(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	database := d.Get("database").(string)
+	store, err := getStoreFromData(d, meta, database)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorStudioPut, err.Error()))
+	}
+
+	err = executeWithRetries(store, &operations.OperationPutStudioConfiguration{
+		Database: database,
+		Configuration: operations.StudioConfiguration{
+			Environment:              d.Get("environment").(string),
+			Disabled:                 d.Get("disabled").(bool),
+			DisableAutoIndexCreation: d.Get("disable_auto_index_creation").(bool),
+		},
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorStudioPut, err.Error()))
+	}
+
+	if database == "" {
+		d.SetId("studio")
+	} else {
+		d.SetId("studio/" + database)
+	}
+
+	return resourceStudioConfigurationRead(ctx, d, meta)
+}
+
+func resourceStudioConfigurationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	database := d.Get("database").(string)
+	store, err := getStoreFromData(d, meta, database)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorStudioRead, err.Error()))
+	}
+
+	operation := operations.OperationGetStudioConfiguration{
+		Database: database,
+	}
+	err = executeWithRetries(store, &operation)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorStudioRead, err.Error()))
+	}
+
+	configuration := operations.StudioConfiguration{Environment: "None"}
+	if operation.Result != nil {
+		configuration = *operation.Result
+	}
+
+	values := map[string]interface{}{
+		"environment":                 configuration.Environment,
+		"disabled":                    configuration.Disabled,
+		"disable_auto_index_creation": configuration.DisableAutoIndexCreation,
+	}
+	for key, value := range values {
+		err = d.Set(key, value)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf(errorStudioRead, err.Error()))
+		}
+	}
+
+	return nil
+}
+
+func resourceStudioConfigurationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	database := d.Get("database").(string)
+	store, err := getStoreFromData(d, meta, database)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorStudioDelete, err.Error()))
+	}
+
+	err = executeWithRetries(store, &operations.OperationPutStudioConfiguration{
+		Database: database,
+		Configuration: operations.StudioConfiguration{
+			Environment: "None",
+		},
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf(errorStudioDelete, err.Error()))
+	}
+
+	return nil
+}
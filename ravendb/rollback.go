@@ -0,0 +1,84 @@
+package ravendb
+
+import (
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// installedHosts records the hosts an apply installed RavenDB on for the first time, the ones a rollback purges. A
+// host retried by deploy_retry is recorded once.
+type installedHosts struct {
+	mu    sync.Mutex
+	hosts map[string]bool
+}
+
+func (h *installedHosts) add(host string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.hosts == nil {
+		h.hosts = map[string]bool{}
+	}
+	h.hosts[host] = true
+}
+
+func (h *installedHosts) list() []string {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	hosts := make([]string, 0, len(h.hosts))
+	for host := range h.hosts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// rollback purges RavenDB from the hosts this apply installed it on, after the deploy failed with rollback_on_failure
+// set. Hosts RavenDB was already installed on are left as they are.
+func (sc *ServerConfig) rollback() diag.Diagnostics {
+	if sc.RollbackOnFailure == false {
+		return nil
+	}
+	hosts := sc.installed.list()
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var result error
+	for _, host := range hosts {
+		wg.Add(1)
+		go func(copyOfHost string) {
+			defer wg.Done()
+			err := sc.purgeRavenDbInstance(copyOfHost)
+			if err != nil {
+				mu.Lock()
+				result = multierror.Append(result, err)
+				mu.Unlock()
+			}
+		}(host)
+	}
+	wg.Wait()
+
+	if result != nil {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "Rolling back the failed deployment failed",
+			Detail:   "RavenDB may still be installed on " + strings.Join(hosts, ", ") + ": " + result.Error(),
+		}}
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  "Rolled back the failed deployment",
+		Detail:   "RavenDB was removed from the hosts it was installed on by this apply: " + strings.Join(hosts, ", "),
+	}}
+}
@@ -1,9 +1,13 @@
 package ravendb
 
 import (
+	"archive/tar"
 	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
 	"crypto/tls"
-	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,14 +15,20 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/ravendb/ravendb-go-client"
 	"github.com/ravendb/ravendb-go-client/serverwide/operations"
-	"github.com/ravendb/terraform-provider-ravendb/utils"
+	internal_operations "github.com/ravendb/terraform-provider-ravendb/operations"
 	"golang.org/x/crypto/ssh"
+	"io"
+	"io/ioutil"
 	"log"
+	"math/rand"
 	"net"
 	"net/url"
+	"os"
+	"path"
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -34,17 +44,78 @@ const (
 	DEFAULT_HTTP_PORT                  int = 80
 )
 
+const (
+	DEPLOY_MODE_PROVISION_AND_CONFIGURE string = "provision_and_configure"
+	DEPLOY_MODE_CONFIGURE_ONLY          string = "configure_only"
+	DEPLOY_MODE_CLUSTER_ONLY            string = "cluster_only"
+)
+
+const (
+	RETRY_BASE_DELAY time.Duration = 2 * time.Second
+	RETRY_MAX_DELAY  time.Duration = 30 * time.Second
+)
+
+// MAX_HOST_RECONNECTS caps the reconnects of a deploy to a single host, so a host that keeps dropping the
+// connection fails the deploy instead of having it resumed forever.
+const MAX_HOST_RECONNECTS int = 5
+
+// resumeCommand finishes the package configuration a reboot interrupted, as apt refuses to run until it is done.
+const resumeCommand = "if command -v dpkg > /dev/null; then sudo dpkg --configure -a; fi"
+
+// configurationFingerprintPath holds the fingerprint of the configuration a node was last restarted with.
+const configurationFingerprintPath = "/etc/ravendb/configuration.sha256"
+
+func init() {
+	rand.Seed(time.Now().UnixNano())
+}
+
 type ServerConfig struct {
 	Package             Package
 	Hosts               []string
 	License             []byte
+	OfflineLicense      bool
 	Settings            map[string]interface{}
+	SettingsMerge       string
+	RemovedSettings     []string
 	ClusterCertificate  []byte
+	TLS                 *TLSOptions
+	SetupPackage        *SetupPackage
+	NodeCertificates    [][]byte
+	NodeArchs           []string
+	NodeTags            []string
 	Url                 Url
-	Assets              map[string][]byte
+	Assets              map[string]Asset
+	Environment         map[string]string
 	Unsecured           bool
+	Connection          string
 	SSH                 SSH
+	dial                dialer
 	HealthcheckDatabase string
+	DeployMode          string
+	Parallel            Parallel
+	Readiness           Readiness
+	Healthcheck         HealthcheckDatabase
+	Monitoring          Monitoring
+	Logging             *Logging
+	TrafficWatch        *TrafficWatch
+	DebugBundleDir      string
+	Preflight           *Preflight
+	DeployRetry         *DeployRetry
+	RollbackOnFailure   bool
+	UnattendedUpgrades  string
+	ClockSync           *ClockSync
+	ManageHostname      bool
+	PeerHosts           *PeerHosts
+	ClusterObserver     *ClusterObserver
+	PostgreSql          *PostgreSql
+	Consul              *Consul
+	Webhook             *Webhook
+	Databases           []Database
+	ClientCertificates  []internal_operations.ClientCertificate
+	stores              *storeCache
+	report              *deploymentReport
+	installed           *installedHosts
+	packages            *packageCache
 }
 
 type NodeState struct {
@@ -58,11 +129,30 @@ type NodeState struct {
 	Unsecured          bool
 	Version            string
 	Failed             bool
+	Warnings           []string
+	ServiceStatus      string
+	DataDiskUsedMb     int
+	DataDiskFreeMb     int
+	MemoryTotalMb      int
+	MemoryAvailableMb  int
+	// CertificateOutdated is set when the certificate on the host is missing or differs from the configured one
+	CertificateOutdated bool
 }
 
 type Package struct {
-	Version string
-	Arch    string
+	Version      string
+	Arch         string
+	Channel      string
+	SkipUrlCheck bool
+	Distribution string
+	CacheDir     string
+}
+
+// Parallel selects the deploy phases that run on all the nodes at once rather than one node after the other.
+type Parallel struct {
+	Install     bool
+	Configure   bool
+	ClusterJoin bool
 }
 
 type Url struct {
@@ -72,9 +162,12 @@ type Url struct {
 }
 
 type SSH struct {
-	User string
-	Pem  []byte
-	Port int
+	User           string
+	Pem            []byte
+	Port           int
+	RebootTimeout  time.Duration
+	HostKeys       map[string]string
+	KnownHostsFile string
 }
 
 func (s *SSH) getPort() int {
@@ -93,62 +186,103 @@ func (e *DeployError) Error() string {
 	return e.Err.Error() + " with output:\n" + e.Output
 }
 
-func upload(con *ssh.Client, buf bytes.Buffer, path string, content []byte) error {
-	//https://chuacw.ath.cx/development/b/chuacw/archive/2019/02/04/how-the-scp-protocol-works.aspx
-	session, err := con.NewSession()
-	if err != nil {
-		return err
+func (e *DeployError) Unwrap() error {
+	return e.Err
+}
+
+func upload(conn Transport, buf *nodeLog, path string, content []byte) error {
+	return uploadWithPermissions(conn, buf, path, content, DEFAULT_ASSET_MODE, "ravendb", "ravendb")
+}
+
+// uploadIfChanged uploads content to path like uploadWithPermissions, unless the file on the host already has
+// that content, mode and owner.
+func uploadIfChanged(conn Transport, buf *nodeLog, path string, content []byte, mode string, owner string, group string) error {
+	digest := sha256.Sum256(content)
+	expected := hex.EncodeToString(digest[:]) + "\n" + mode + " " + owner + ":" + group
+	current, err := runCommand(conn, "sudo sha256sum "+path+" | cut -d ' ' -f 1 && sudo stat -c '%04a %U:%G' "+path)
+	if err == nil && strings.TrimSpace(string(current)) == expected {
+		buf.WriteString(path + " is unchanged\n")
+		return nil
 	}
+	return uploadWithPermissions(conn, buf, path, content, mode, owner, group)
+}
 
-	buf.WriteString("sudo scp -t " + path + "\n")
+// uploadWithPermissions copies content to path on the host, owned by owner:group with mode, e.g. 0660.
+func uploadWithPermissions(conn Transport, buf *nodeLog, path string, content []byte, mode string, owner string, group string) error {
+	return uploadReaderWithPermissions(conn, buf, path, bytes.NewReader(content), int64(len(content)), mode, owner, group)
+}
 
-	stdin, err := session.StdinPipe()
+// uploadFileWithPermissions copies the local file at localPath to path on the host like uploadWithPermissions,
+// streaming it instead of reading it in memory.
+func uploadFileWithPermissions(conn Transport, buf *nodeLog, path string, localPath string, mode string, owner string, group string) error {
+	file, err := os.Open(localPath)
 	if err != nil {
 		return err
 	}
-	go func() {
-		defer stdin.Close()
-		fmt.Fprint(stdin, "C0660 "+strconv.Itoa(len(content))+" file\n")
-		stdin.Write(content)
-		fmt.Fprint(stdin, "\x00")
-	}()
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	return uploadReaderWithPermissions(conn, buf, path, file, info.Size(), mode, owner, group)
+}
 
-	output, err := session.CombinedOutput("sudo scp -t " + path)
-	buf.Write(output)
+func uploadReaderWithPermissions(conn Transport, buf *nodeLog, path string, content io.Reader, size int64, mode string, owner string, group string) error {
+	buf.WriteString("Uploading " + path + "\n")
+	err := conn.Upload(path, content, size, mode)
 	if err != nil {
+		buf.WriteString(err.Error() + "\n")
 		return &DeployError{
 			Err:    err,
 			Output: buf.String(),
 		}
 	}
 
-	session.Close()
+	// the upload only sets the mode of the files it creates
+	cmd := "sudo chown " + owner + ":" + group + " " + path + " && sudo chmod " + mode + " " + path
+	buf.WriteString(cmd + "\n")
 
-	session, err = con.NewSession()
+	output, err := runCommand(conn, cmd)
+	buf.Write(output)
 	if err != nil {
-		return err
+		return errors.New("Failed to ownership: " + path + "\n" + err.Error() + "\n")
 	}
-	defer session.Close()
 
-	buf.WriteString("sudo chown ravendb:ravendb " + path + "\n")
+	return nil
+}
 
-	output, err = session.CombinedOutput("sudo chown ravendb:ravendb " + path)
-	buf.Write(output)
+// deployRavenDbInstances checks the hosts, installs RavenDB on all of them and then configures them. The install
+// and configure phases run on the hosts in parallel or one after the other, as set by sc.Parallel.
+func (sc *ServerConfig) deployRavenDbInstances() error {
+	err := sc.runPreflight()
 	if err != nil {
-		return errors.New("Failed to ownership: " + path + "\n" + err.Error() + "\n")
+		return err
 	}
+	err = sc.forEachHost(sc.Parallel.Install, sc.report.timedOnHost("install", sc.withRetries("install", sc.installServer, sc.cleanupInstall)))
+	sc.packages.close()
+	if err != nil {
+		return err
+	}
+	err = sc.writePeerHosts()
+	if err != nil {
+		return err
+	}
+	return sc.configureNodes()
+}
 
-	return nil
+// configureNodes runs the configure phase on all the hosts.
+func (sc *ServerConfig) configureNodes() error {
+	return sc.forEachHost(sc.Parallel.Configure, sc.report.timedOnHost("configure", sc.withRetries("configure", sc.configureServer, nil)))
 }
 
-func (sc *ServerConfig) deployRavenDbInstances(parallel bool) error {
+func (sc *ServerConfig) forEachHost(parallel bool, action func(publicIP string, index int) error) error {
 	var wg sync.WaitGroup
 	errorsChannel := make(chan error, len(sc.Hosts))
 
 	for index, publicIp := range sc.Hosts {
 		wg.Add(1)
 		deployAction := func(copyOfPublicIp string, copyOfIndex int) {
-			err := sc.deployServer(copyOfPublicIp, copyOfIndex)
+			err := action(copyOfPublicIp, copyOfIndex)
 			if err != nil {
 				errorsChannel <- err
 			}
@@ -172,63 +306,93 @@ func (sc *ServerConfig) deployRavenDbInstances(parallel bool) error {
 	return result
 }
 
-func listFiles(conn *ssh.Client, dir string) ([]string, error) {
-	session, err := conn.NewSession()
-	if err != nil {
-		return nil, err
+// secretFiles are never fetched from the configuration directory of a node.
+var secretFiles = []string{"master.key"}
+
+// readConfigurationFiles fetches all the files directly under dir in a single round trip, as a
+// base64 encoded tarball. It returns the file contents and the names of the secret files skipped.
+func readConfigurationFiles(conn Transport, dir string, stdoutBuf *nodeLog) (map[string][]byte, []string, error) {
+	var exclude strings.Builder
+	var report strings.Builder
+	for _, secret := range secretFiles {
+		exclude.WriteString(" ! -name '" + secret + "'")
+		report.WriteString("[ -f '" + secret + "' ] && echo 'skipped:" + secret + "' >&2; ")
+	}
+	cmd := "sudo sh -c \"cd '" + dir + "' && " + report.String() +
+		"find . -maxdepth 1 -type f" + exclude.String() + " -print0 | tar --null -T - -czf - || echo 'failed:tar' >&2\" | base64 -w0"
+
+	var output, stderr bytes.Buffer
+	stdoutBuf.WriteString("$ " + cmd + "\n")
+	err := conn.Run(cmd, &output, &stderr)
+	// the exit status of the pipeline is the one of base64, a failed tar is only reported on stderr
+	if err == nil && strings.Contains(stderr.String(), "failed:tar") {
+		err = errors.New("failed to archive the files of " + dir)
 	}
-	output, err := session.CombinedOutput("sudo find '" + dir + "' -type f  -maxdepth 1")
 	if err != nil {
-		return nil, errors.New(string(output))
+		stdoutBuf.Write(stderr.Bytes())
+		return nil, nil, &DeployError{
+			Err:    err,
+			Output: stdoutBuf.String(),
+		}
 	}
-	str := string(output)
-	lines := strings.Split(str, "\n")
-	return lines[:len(lines)-1], nil
-}
-
-func (sc *ServerConfig) ReadServer(publicIP string, index int) (NodeState, error) {
 
-	var stdoutBuf bytes.Buffer
-	var ns NodeState
-	var conn *ssh.Client
-	defer func() {
-		log.Println(stdoutBuf.String())
-	}()
+	var skipped []string
+	for _, line := range strings.Split(stderr.String(), "\n") {
+		if strings.HasPrefix(line, "skipped:") {
+			skipped = append(skipped, strings.TrimPrefix(line, "skipped:"))
+		}
+	}
 
-	signer, err := ssh.ParsePrivateKey(sc.SSH.Pem)
+	archive, err := base64.StdEncoding.DecodeString(strings.TrimSpace(output.String()))
 	if err != nil {
-		return ns, err
+		return nil, nil, err
 	}
+	gzipReader, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer gzipReader.Close()
 
-	authConfig := &ssh.ClientConfig{
-		User:            sc.SSH.User,
-		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-		Timeout:         time.Second * 10,
+	files := make(map[string][]byte)
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		contents, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			return nil, nil, err
+		}
+		_, fileName := filepath.Split(header.Name)
+		files[fileName] = contents
 	}
+	return files, skipped, nil
+}
 
-	conn, err = sc.ConnectToRemoteWithRetry(publicIP, conn, authConfig)
+func (sc *ServerConfig) ReadServer(publicIP string, index int) (NodeState, error) {
+
+	stdoutBuf := newNodeLog(publicIP)
+	var ns NodeState
+	defer stdoutBuf.flush()
+
+	conn, err := sc.ConnectToRemoteWithRetry(publicIP, time.Second*10)
 	if err != nil {
 		return ns, err
 	}
 
 	defer conn.Close()
-	files, err := listFiles(conn, "/etc/ravendb")
+	var skipped []string
+	ns.Assets, skipped, err = readConfigurationFiles(conn, "/etc/ravendb", stdoutBuf)
 	if err != nil {
 		return ns, err
 	}
-	ns.Assets = make(map[string][]byte)
-	for _, file := range files {
-		_, fileName := filepath.Split(file)
-
-		contents, err := readFileContents(file, stdoutBuf, conn)
-		if err != nil {
-			return ns, err
-		}
-		if contents == nil {
-			contents = make([]byte, 0) // empty file
-		}
-		ns.Assets[fileName] = contents
+	for _, fileName := range skipped {
+		// the encryption master key must never end up in the terraform state
+		ns.Warnings = append(ns.Warnings, "Skipped reading /etc/ravendb/"+fileName+" on "+publicIP+" because it holds the server master key")
 	}
 
 	ns.Settings = make(map[string]interface{})
@@ -241,6 +405,8 @@ func (sc *ServerConfig) ReadServer(publicIP string, index int) (NodeState, error
 		}
 		delete(ns.Assets, "settings.json")
 	}
+	// verified before the values are stringified, as the filters are arrays
+	ns.Warnings = append(ns.Warnings, sc.TrafficWatch.verify(publicIP, ns.Settings)...)
 	//workaround to convert unmarshalled map[string]interface{} values to string.
 	for key := range ns.Settings {
 		ns.Settings[key] = fmt.Sprintf("%v", ns.Settings[key])
@@ -251,10 +417,17 @@ func (sc *ServerConfig) ReadServer(publicIP string, index int) (NodeState, error
 		delete(ns.Assets, "license.json")
 	}
 
-	if cert, ok := ns.Assets["certificate.pfx"]; ok {
+	if cert, ok := ns.Assets[path.Base(nodeCertificatePath)]; ok {
 		ns.ClusterCertificate = cert
-		delete(ns.Assets, "certificate.pfx")
+		delete(ns.Assets, path.Base(nodeCertificatePath))
 	}
+	if sc.certificateDrifted(index, ns.ClusterCertificate) {
+		ns.CertificateOutdated = true
+		ns.Warnings = append(ns.Warnings, "The certificate of "+publicIP+" is missing or differs from the configured one, the next apply restores it and restarts the node")
+	}
+	delete(ns.Assets, path.Base(configurationFingerprintPath))
+	// the environment may hold credentials, it isn't part of the refreshed assets
+	delete(ns.Assets, path.Base(environmentFilePath))
 
 	store, err := getStore(sc, index)
 	if err != nil {
@@ -275,6 +448,8 @@ func (sc *ServerConfig) ReadServer(publicIP string, index int) (NodeState, error
 		ns.Unsecured = unsecuredAccessAllowed == "PublicNetwork"
 	}
 
+	ns.readHealth(conn)
+
 	delete(ns.Settings, "PublicServerUrl")
 	delete(ns.Settings, "PublicServerUrl.Tcp")
 	delete(ns.Settings, "Security.UnsecuredAccessAllowed")
@@ -282,40 +457,164 @@ func (sc *ServerConfig) ReadServer(publicIP string, index int) (NodeState, error
 	return ns, nil
 }
 
-func (sc *ServerConfig) deployServer(publicIP string, index int) (err error) {
-	var stdoutBuf bytes.Buffer
-	var conn *ssh.Client
-	defer func() {
-		log.Println(stdoutBuf.String())
-	}()
-	ravenPackageUrl := "https://daily-builds.s3.us-east-1.amazonaws.com/ravendb_" + sc.Package.Version + sc.Package.Arch
+// hostStep is a unit of work done on a host through its transport.
+type hostStep func(conn Transport, stdoutBuf *nodeLog) error
+
+// onHost connects to publicIP and runs steps on it, one after the other. When the connection is lost, e.g.
+// because the host rebooted to apply a kernel update, it waits up to sc.SSH.RebootTimeout for the host to come
+// back, finishes the package configuration the reboot may have interrupted and resumes from the step that was
+// interrupted, up to MAX_HOST_RECONNECTS times. The output of the steps is logged as they run, and a debug bundle
+// is collected when they fail.
+func (sc *ServerConfig) onHost(publicIP string, steps ...hostStep) (err error) {
+	stdoutBuf := newNodeLog(publicIP)
+	defer stdoutBuf.flush()
 
-	signer, err := ssh.ParsePrivateKey(sc.SSH.Pem)
+	conn, err := sc.ConnectToRemoteWithRetry(publicIP, 1*time.Minute)
 	if err != nil {
 		return err
 	}
-	authConfig := &ssh.ClientConfig{
-		User:            sc.SSH.User,
-		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-		Timeout:         1 * time.Minute,
+	defer func() {
+		conn.Close()
+	}()
+	defer func() {
+		if err != nil && sc.DebugBundleDir != "" {
+			bundlePath, bundleErr := collectDebugBundle(conn, sc.DebugBundleDir, publicIP, stdoutBuf.Bytes())
+			err = withDebugBundle(err, bundlePath, bundleErr)
+		}
+	}()
+
+	reconnects := 0
+	for i := 0; i < len(steps); {
+		err = steps[i](conn, stdoutBuf)
+		if err == nil {
+			i++
+			continue
+		}
+		if sc.SSH.RebootTimeout == 0 || !isConnectionLost(err) {
+			return err
+		}
+		if reconnects == MAX_HOST_RECONNECTS {
+			return errors.New("lost the connection to " + publicIP + " " + strconv.Itoa(reconnects+1) + " times, giving up: " + err.Error())
+		}
+		reconnects++
+		stdoutBuf.WriteString("Lost the connection, waiting for the host to come back: " + err.Error() + "\n")
+		reconnected, reconnectErr := sc.reconnect(publicIP, 1*time.Minute)
+		if reconnectErr != nil {
+			return reconnectErr
+		}
+		conn.Close()
+		conn = reconnected
+		sc.report.rebooted(publicIP)
+
+		err = sc.execute(publicIP, []string{resumeCommand}, "", stdoutBuf, conn)
+		if err != nil && !isConnectionLost(err) {
+			return err
+		}
 	}
-	conn, err = sc.ConnectToRemoteWithRetry(publicIP, conn, authConfig)
-	if err != nil {
-		return err
+	return nil
+}
+
+// isConnectionLost reports whether err was caused by the connection to the host going away rather than by a
+// command failing.
+func isConnectionLost(err error) bool {
+	var exitMissing *ssh.ExitMissingError
+	var netErr net.Error
+	return errors.As(err, &exitMissing) || errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// reconnect dials publicIP until it accepts connections again or sc.SSH.RebootTimeout expires.
+func (sc *ServerConfig) reconnect(publicIP string, timeout time.Duration) (Transport, error) {
+	hostAndPort := net.JoinHostPort(publicIP, fmt.Sprint(sc.SSH.getPort()))
+	deadline := time.Now().Add(sc.SSH.RebootTimeout)
+	for {
+		// give a rebooting host the time to actually go down before trying to reach it again
+		time.Sleep(5 * time.Second)
+		conn, err := sc.dialHost(publicIP, timeout)
+		if err == nil {
+			log.Println("Reconnected to " + hostAndPort)
+			return conn, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.New("host " + hostAndPort + " did not come back within " + sc.SSH.RebootTimeout.String() + ": " + err.Error())
+		}
 	}
-	defer conn.Close()
-	err = sc.execute(publicIP, []string{
-		"n=0; while [ \"$n\" -lt 10 ] && [ ! -f /var/lib/cloud/instance/boot-finished ]; do echo 'Waiting for cloud-init...'; n=$(( n + 1 )); sleep 1; done",
-		"wget -nv -O ravendb.deb " + ravenPackageUrl,
-		"timeout 100 bash -c -- 'while ! sudo apt-get update -y; do sleep 1; done'",
-		"sudo apt-get install -y -f ./ravendb.deb",
-	}, "", &stdoutBuf, conn)
+}
+
+// command returns a step that runs a single command, so a deploy interrupted by a reboot resumes from it.
+func (sc *ServerConfig) command(publicIP string, cmd string) hostStep {
+	return func(conn Transport, stdoutBuf *nodeLog) error {
+		return sc.execute(publicIP, []string{cmd}, "", stdoutBuf, conn)
+	}
+}
+
+func (sc *ServerConfig) installServer(publicIP string, index int) error {
+	steps, err := sc.prepareHost(publicIP, index)
 	if err != nil {
 		return err
 	}
+	if sc.DeployMode == DEPLOY_MODE_CONFIGURE_ONLY {
+		// RavenDB was installed by another tool (e.g. baked into the image), only make sure it is there
+		steps = append(steps, func(conn Transport, stdoutBuf *nodeLog) error {
+			return sc.execute(publicIP, []string{
+				"test -f /etc/ravendb/settings.json || { echo 'RavenDB is not installed: /etc/ravendb/settings.json is missing'; exit 1; }",
+				"systemctl cat ravendb > /dev/null",
+			}, "", stdoutBuf, conn)
+		})
+		return sc.onHost(publicIP, steps...)
+	}
+	var family string
+	var installed bool
+	steps = append(steps,
+		sc.command(publicIP, "n=0; while [ \"$n\" -lt 10 ] && [ ! -f /var/lib/cloud/instance/boot-finished ]; do echo 'Waiting for cloud-init...'; n=$(( n + 1 )); sleep 1; done"),
+		func(conn Transport, stdoutBuf *nodeLog) (err error) {
+			family, err = detectOsFamily(conn)
+			if err != nil {
+				return err
+			}
+			installed = installedVersion(conn, family) == sc.Package.Version
+			if installed {
+				stdoutBuf.WriteString("RavenDB " + sc.Package.Version + " is already installed and running, skipping the installation\n")
+				return nil
+			}
+			if _, err := runCommand(conn, "test -e /usr/lib/ravendb/server/Raven.Server"); err != nil {
+				sc.installed.add(publicIP)
+			}
+			stdoutBuf.WriteString("Installing RavenDB for a " + family + " based distribution\n")
+			return nil
+		},
+		func(conn Transport, stdoutBuf *nodeLog) error {
+			if installed || sc.Package.Distribution != PACKAGE_DISTRIBUTION_PUSH {
+				return nil
+			}
+			return sc.pushPackage(conn, stdoutBuf, family, sc.nodePackage(index))
+		},
+		func(conn Transport, stdoutBuf *nodeLog) error {
+			if installed {
+				return nil
+			}
+			return sc.execute(publicIP, sc.installCommands(family, sc.nodePackage(index)), "", stdoutBuf, conn)
+		},
+	)
+	return sc.onHost(publicIP, steps...)
+}
 
-	err = upload(conn, stdoutBuf, "/etc/ravendb/license.json", sc.License)
+func (sc *ServerConfig) configureServer(publicIP string, index int) error {
+	return sc.onHost(publicIP, func(conn Transport, stdoutBuf *nodeLog) error {
+		return sc.configureNode(publicIP, index, conn, stdoutBuf)
+	})
+}
+
+func (sc *ServerConfig) configureNode(publicIP string, index int, conn Transport, stdoutBuf *nodeLog) error {
+	// every file written below goes into the fingerprint of the configuration, so an unchanged node isn't restarted
+	fingerprint := sha256.New()
+	fingerprint.Write([]byte(sc.Package.Version))
+	put := func(path string, content []byte) error {
+		fingerprint.Write([]byte(path))
+		fingerprint.Write(content)
+		return uploadIfChanged(conn, stdoutBuf, path, content, DEFAULT_ASSET_MODE, "ravendb", "ravendb")
+	}
+
+	err := put("/etc/ravendb/license.json", sc.License)
 	if err != nil {
 		return err
 	}
@@ -332,41 +631,129 @@ func (sc *ServerConfig) deployServer(publicIP string, index int) (err error) {
 		stdoutBuf.Write(contents)
 		return err
 	}
+	settings, err = sc.settingsBase(settings)
+	if err != nil {
+		return err
+	}
 
-	for path, content := range sc.Assets {
+	// the assets are uploaded in a stable order, so the fingerprint doesn't change with the map iteration order
+	assetPaths := make([]string, 0, len(sc.Assets))
+	for path := range sc.Assets {
+		assetPaths = append(assetPaths, path)
+	}
+	sort.Strings(assetPaths)
+	for _, path := range assetPaths {
+		asset := sc.Assets[path]
 		splittedPath := strings.Split(path, "/")
 		directories := splittedPath[1 : len(splittedPath)-1]
 		absolutePath := strings.Join(directories, "/")
 
 		err = sc.execute(publicIP, []string{
 			"sudo mkdir -p /" + absolutePath,
-		}, "", &stdoutBuf, conn)
+		}, "", stdoutBuf, conn)
 		if err != nil {
 			return err
 		}
 
-		err = upload(conn, stdoutBuf, path, content)
+		fingerprint.Write([]byte(path + " " + asset.Mode + " " + asset.Owner + ":" + asset.Group))
+		fingerprint.Write(asset.Content)
+		err = uploadIfChanged(conn, stdoutBuf, path, asset.Content, asset.Mode, asset.Owner, asset.Group)
 		if err != nil {
 			return err
 		}
 	}
 
-	if sc.ClusterCertificate != nil && sc.Unsecured == false {
-		settings["Security.Certificate.Path"] = "/etc/ravendb/certificate.pfx"
-		err = upload(conn, stdoutBuf, "/etc/ravendb/certificate.pfx", sc.ClusterCertificate)
+	err = sc.writeEnvironment(publicIP, conn, stdoutBuf, fingerprint)
+	if err != nil {
+		return err
+	}
+
+	// a certificate replaced on the host, e.g. by a disk restore, isn't part of the fingerprint but needs a restart as well
+	certificateChanged := false
+	if certificate := sc.nodeCertificate(index); certificate != nil && sc.Unsecured == false {
+		certificateChanged = certificateOutdated(conn, certificate)
+		settings["Security.Certificate.Path"] = nodeCertificatePath
+		err = put(nodeCertificatePath, certificate)
 		if err != nil {
 			return err
 		}
 
 		err = sc.execute(publicIP, []string{
-			"sudo chown ravendb:ravendb /etc/ravendb/certificate.pfx",
-		}, "sudo systemctl status ravendb", &stdoutBuf, conn)
+			"sudo chown ravendb:ravendb " + nodeCertificatePath,
+		}, "sudo systemctl status ravendb", stdoutBuf, conn)
 		if err != nil {
 			return err
 		}
 
 	}
 
+	httpUrl, err := sc.applySettings(index, settings)
+	if err != nil {
+		return err
+	}
+
+	jsonOut, err := json.MarshalIndent(settings, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	err = put("/etc/ravendb/settings.json", jsonOut)
+	if err != nil {
+		return err
+	}
+
+	if sc.Monitoring.PrometheusTargetPath != "" {
+		target, err := sc.Monitoring.prometheusTarget(httpUrl, publicIP)
+		if err != nil {
+			return err
+		}
+		err = sc.execute(publicIP, []string{
+			"sudo mkdir -p " + path.Dir(sc.Monitoring.PrometheusTargetPath),
+		}, "", stdoutBuf, conn)
+		if err != nil {
+			return err
+		}
+		err = put(sc.Monitoring.PrometheusTargetPath, target)
+		if err != nil {
+			return err
+		}
+		err = sc.execute(publicIP, []string{
+			"sudo chmod 0644 " + sc.Monitoring.PrometheusTargetPath,
+		}, "", stdoutBuf, conn)
+		if err != nil {
+			return err
+		}
+	}
+	digest := hex.EncodeToString(fingerprint.Sum(nil))
+	deployed, _ := runCommand(conn, "sudo cat "+configurationFingerprintPath)
+	_, inactive := runCommand(conn, "systemctl is-active --quiet ravendb")
+	restart := inactive != nil || certificateChanged || strings.TrimSpace(string(deployed)) != digest
+	commands := []string{"sudo chown ravendb:ravendb /etc/ravendb/license.json"}
+	if restart {
+		commands = append(commands, "sudo systemctl restart ravendb")
+	} else {
+		stdoutBuf.WriteString("The configuration is unchanged, RavenDB is not restarted\n")
+	}
+	err = sc.execute(publicIP, append(commands,
+		"timeout 100 bash -c -- 'while ! curl  -v "+httpUrl+"/setup/alive; do sleep 1; done'",
+	), "sudo systemctl status ravendb", stdoutBuf, conn)
+	if err != nil {
+		return err
+	}
+	if restart {
+		sc.report.restarted(publicIP)
+		err = sc.checkNodeHealth(publicIP, index, httpUrl, conn, stdoutBuf)
+		if err != nil {
+			return err
+		}
+	}
+
+	return upload(conn, stdoutBuf, configurationFingerprintPath, []byte(digest))
+}
+
+// applySettings sets the urls and the configured settings of the node at index in settings, and returns the http
+// url of the node.
+func (sc *ServerConfig) applySettings(index int, settings map[string]interface{}) (string, error) {
 	scheme := "https"
 	if sc.Unsecured {
 		settings["Security.UnsecuredAccessAllowed"] = "PublicNetwork"
@@ -374,7 +761,7 @@ func (sc *ServerConfig) deployServer(publicIP string, index int) (err error) {
 	}
 	httpUrl, err := sc.setupUrls(index, scheme, settings)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	settings["ServerUrl"] = scheme + "://0.0.0.0:" + strconv.Itoa(sc.Url.HttpPort)
@@ -382,37 +769,30 @@ func (sc *ServerConfig) deployServer(publicIP string, index int) (err error) {
 	settings["Setup.Mode"] = "None"
 	settings["License.Path"] = "/etc/ravendb/license.json"
 
-	for key, value := range sc.Settings {
-		settings[key] = value
-	}
-
-	jsonOut, err := json.MarshalIndent(settings, "", "\t")
-	if err != nil {
-		return err
+	sc.Monitoring.applyTo(settings)
+	sc.Logging.applyTo(settings)
+	sc.TrafficWatch.applyTo(settings)
+	sc.ClusterObserver.applyTo(settings)
+	if sc.OfflineLicense {
+		for key, value := range offlineLicenseSettings {
+			settings[key] = value
+		}
 	}
+	sc.PostgreSql.applyTo(settings)
 
-	err = upload(conn, stdoutBuf, "/etc/ravendb/settings.json", jsonOut)
-	if err != nil {
-		return err
-	}
-	err = sc.execute(publicIP, []string{
-		"sudo chown ravendb:ravendb /etc/ravendb/license.json",
-		"sudo systemctl restart ravendb",
-		"timeout 100 bash -c -- 'while ! curl  -v " + httpUrl + "/setup/alive; do sleep 1; done'",
-	}, "sudo systemctl status ravendb", &stdoutBuf, conn)
-	if err != nil {
-		return err
+	for key, value := range sc.Settings {
+		settings[key] = value
 	}
-
-	return nil
+	return httpUrl, nil
 }
 
-func (sc *ServerConfig) ConnectToRemoteWithRetry(publicIP string, conn *ssh.Client, authConfig *ssh.ClientConfig) (*ssh.Client, error) {
+func (sc *ServerConfig) ConnectToRemoteWithRetry(publicIP string, timeout time.Duration) (Transport, error) {
+	var conn Transport
 	var err error
 	hostAndPort := net.JoinHostPort(publicIP, fmt.Sprint(sc.SSH.getPort()))
 	log.Println("Trying to SHH: " + hostAndPort)
 	for i := 0; i <= NUMBER_OF_RETRIES; i++ {
-		conn, err = ssh.Dial("tcp", hostAndPort, authConfig)
+		conn, err = sc.dialHost(publicIP, timeout)
 		if err != nil && i < NUMBER_OF_RETRIES {
 			time.Sleep(time.Second * 2)
 		} else if err == nil {
@@ -472,61 +852,131 @@ func (sc *ServerConfig) maybeAddHttpPortToHost(host string) string {
 	return host
 }
 
-type debugWriter struct {
-	mu        sync.Mutex
-	stdoutBuf *bytes.Buffer
+// readHealth fills the service status, disk and memory usage of ns from the host of conn. Values that can't be
+// read are left empty, they must not fail the refresh.
+func (ns *NodeState) readHealth(conn Transport) {
+	status, _ := runCommand(conn, "systemctl is-active ravendb")
+	ns.ServiceStatus = strings.TrimSpace(string(status))
+
+	dataDir := "/var/lib/ravendb/data"
+	if dir, ok := ns.Settings["DataDir"].(string); ok && path.IsAbs(dir) {
+		dataDir = dir
+	}
+	disk, err := runCommand(conn, "df -Pm '"+dataDir+"' | awk 'NR == 2 { print $3, $4 }'")
+	if err == nil {
+		fmt.Sscan(string(disk), &ns.DataDiskUsedMb, &ns.DataDiskFreeMb)
+	}
+
+	memory, err := runCommand(conn, "awk '/^MemTotal:/ { total = $2 } /^MemAvailable:/ { available = $2 } END { print int(total / 1024), int(available / 1024) }' /proc/meminfo")
+	if err == nil {
+		fmt.Sscan(string(memory), &ns.MemoryTotalMb, &ns.MemoryAvailableMb)
+	}
+}
+
+// nodeLog collects the output of the commands run on a single node. Every complete line is written to the log
+// as soon as it arrives, prefixed with the host so that nodes deployed in parallel can be told apart, while the
+// whole output is kept for the errors and debug bundles of failed deploys.
+type nodeLog struct {
+	mu      sync.Mutex
+	host    string
+	buf     bytes.Buffer
+	pending []byte
 }
 
-func (w *debugWriter) Write(p []byte) (int, error) {
-	w.mu.Lock()
-	defer w.mu.Unlock()
-	w.stdoutBuf.Write(p)
-	return len(p), nil
+func newNodeLog(host string) *nodeLog {
+	return &nodeLog{host: host}
 }
 
-func (sc *ServerConfig) execute(publicIp string, commands []string, onErr string, stdoutBuf *bytes.Buffer, conn *ssh.Client) error {
-	writer := debugWriter{
-		stdoutBuf: stdoutBuf,
-	}
-	stdoutBuf.WriteString(publicIp)
-	for _, cmd := range commands {
-		cmdStr := "$ " + cmd + "\n"
-		stdoutBuf.WriteString(cmdStr)
-		writer.Write([]byte(cmdStr))
-		session, err := conn.NewSession()
-		if err != nil {
-			return err
+func (l *nodeLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pending = append(l.pending, p...)
+	for {
+		end := bytes.IndexByte(l.pending, '\n')
+		if end < 0 {
+			break
 		}
+		l.print(l.pending[:end])
+		l.pending = l.pending[end+1:]
+	}
+	return l.buf.Write(p)
+}
+
+func (l *nodeLog) WriteString(s string) (int, error) {
+	return l.Write([]byte(s))
+}
+
+func (l *nodeLog) Bytes() []byte {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]byte(nil), l.buf.Bytes()...)
+}
+
+func (l *nodeLog) String() string {
+	return string(l.Bytes())
+}
+
+func (l *nodeLog) print(line []byte) {
+	log.Print("[" + l.host + "] " + strings.TrimRight(string(line), "\r"))
+}
 
-		session.Stdout = &writer
-		session.Stderr = &writer
+// flush logs the last line when the output didn't end with a newline.
+func (l *nodeLog) flush() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.pending) > 0 {
+		l.print(l.pending)
+		l.pending = nil
+	}
+}
 
-		err = session.Run(cmd)
+func (sc *ServerConfig) execute(publicIp string, commands []string, onErr string, stdoutBuf *nodeLog, conn Transport) error {
+	for _, cmd := range commands {
+		stdoutBuf.WriteString("$ " + cmd + "\n")
+		err := conn.Run(cmd, stdoutBuf, stdoutBuf)
 		if err != nil {
 			log.Println(err)
 			if onErr != "" {
-				session.Run(cmd) // executed to write to the log
+				conn.Run(cmd, stdoutBuf, stdoutBuf) // executed to write to the log
 			}
-			session.Close()
 
 			return &DeployError{
 				Err:    err,
 				Output: stdoutBuf.String(),
 			}
 		}
-		session.Close()
 	}
 	return nil
 }
 
-func (sc *ServerConfig) Deploy(parallel bool) (string, error) {
-	var databaseDoesNotExistError *ravendb.DatabaseDoesNotExistError
-	store, err := getStore(sc, 0)
+func (sc *ServerConfig) Deploy() (string, error) {
+	if sc.DeployMode != DEPLOY_MODE_CLUSTER_ONLY {
+		err := sc.deployRavenDbInstances()
+		if err != nil {
+			return "", err
+		}
+	}
+	err := sc.report.timed("wait_for_nodes", sc.waitForNodes)
 	if err != nil {
 		return "", err
 	}
 
-	err = sc.deployRavenDbInstances(parallel)
+	var id string
+	err = sc.report.timed("configure_cluster", func() (err error) {
+		id, err = sc.configureCluster()
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, sc.report.timed("wait_for_cluster", sc.waitForCluster)
+}
+
+// configureCluster joins the nodes into a cluster and creates the databases on it, returning the topology id.
+// The nodes are expected to be deployed already.
+func (sc *ServerConfig) configureCluster() (string, error) {
+	var databaseDoesNotExistError *ravendb.DatabaseDoesNotExistError
+	store, err := getStore(sc, 0)
 	if err != nil {
 		return "", err
 	}
@@ -540,6 +990,22 @@ func (sc *ServerConfig) Deploy(parallel bool) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	err = sc.checkNodeTags(clusterTopology.Topology.AllNodes)
+	if err != nil {
+		return "", err
+	}
+
+	if sc.OfflineLicense && sc.License != nil {
+		err = executeWithRetries(store, &internal_operations.OperationActivateLicense{License: sc.License})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = sc.registerClientCertificates(store)
+	if err != nil {
+		return "", err
+	}
 
 	err = sc.getDatabaseHealthCheck(store)
 	if errors.As(err, &databaseDoesNotExistError) {
@@ -551,9 +1017,41 @@ func (sc *ServerConfig) Deploy(parallel bool) (string, error) {
 		return "", err
 	}
 
+	err = sc.createDatabases(store)
+	if err != nil {
+		return "", err
+	}
+
+	err = sc.addPostgreSqlUsers(store)
+	if err != nil {
+		return "", err
+	}
+
+	err = sc.Consul.register(sc)
+	if err != nil {
+		return "", err
+	}
+
 	return clusterTopology.Topology.TopologyID, nil
 }
 
+func (sc *ServerConfig) addPostgreSqlUsers(store *ravendb.DocumentStore) error {
+	if sc.PostgreSql == nil {
+		return nil
+	}
+	for _, user := range sc.PostgreSql.Users {
+		err := executeWithRetries(store, &internal_operations.OperationPutPostgreSqlUser{
+			Database: user.Database,
+			Username: user.Username,
+			Password: user.Password,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (sc *ServerConfig) getDatabaseHealthCheck(store *ravendb.DocumentStore) error {
 	databaseHealthCheck := operations.OperationDatabaseHealthCheck{}
 	err := executeWithRetriesMaintenanceOperations(store, &databaseHealthCheck)
@@ -563,56 +1061,92 @@ func (sc *ServerConfig) getDatabaseHealthCheck(store *ravendb.DocumentStore) err
 	return nil
 }
 
+// clusterTopologies holds the last cluster topology read through each store of a storeCache.
+type clusterTopologies map[*ravendb.DocumentStore]operations.OperationGetClusterTopology
+
+// getClusterTopology returns the cluster topology last read through store, or reads it when it wasn't read yet or
+// changed since. Waiting for the topology to change takes refreshClusterTopology.
 func (sc *ServerConfig) getClusterTopology(store *ravendb.DocumentStore) (operations.OperationGetClusterTopology, error) {
+	if sc.stores != nil {
+		sc.stores.mu.Lock()
+		clusterTopology, ok := sc.stores.topologies[store]
+		sc.stores.mu.Unlock()
+		if ok {
+			return clusterTopology, nil
+		}
+	}
+	return sc.refreshClusterTopology(store)
+}
+
+// refreshClusterTopology reads the cluster topology through store.
+func (sc *ServerConfig) refreshClusterTopology(store *ravendb.DocumentStore) (operations.OperationGetClusterTopology, error) {
+	return sc.readClusterTopology(store, executeWithRetries)
+}
+
+// readClusterTopology reads the cluster topology through store with send, and caches it.
+func (sc *ServerConfig) readClusterTopology(store *ravendb.DocumentStore, send func(*ravendb.DocumentStore, ravendb.IServerOperation) error) (operations.OperationGetClusterTopology, error) {
 	clusterTopology := operations.OperationGetClusterTopology{}
-	err := executeWithRetries(store, &clusterTopology)
+	err := send(store, &clusterTopology)
 	if err != nil {
+		sc.forgetClusterTopology(store)
 		return operations.OperationGetClusterTopology{}, err
 	}
+	if sc.stores != nil {
+		sc.stores.mu.Lock()
+		sc.stores.topologies[store] = clusterTopology
+		sc.stores.mu.Unlock()
+	}
 	return clusterTopology, nil
 }
 
-func getStore(config *ServerConfig, index int) (*ravendb.DocumentStore, error) {
-	var host string
-
-	host = config.Url.List[index]
-
-	serverNode := []string{host}
-	store := ravendb.NewDocumentStore(serverNode, config.HealthcheckDatabase)
+// forgetClusterTopology drops the topology read through store, once the cluster was changed through it.
+func (sc *ServerConfig) forgetClusterTopology(store *ravendb.DocumentStore) {
+	if sc.stores == nil {
+		return
+	}
+	sc.stores.mu.Lock()
+	delete(sc.stores.topologies, store)
+	sc.stores.mu.Unlock()
+}
 
+// getStore returns the store of the node at index, reusing the one already initialized when the config carries
+// a store cache, the one of the provider meta once the resources parsed their configuration.
+func getStore(config *ServerConfig, index int) (*ravendb.DocumentStore, error) {
+	nodeUrl := config.Url.List[index]
+	var certificate *tls.Certificate
 	if config.Unsecured == false {
-		key, crt, err := utils.PfxToPem(config.ClusterCertificate)
-
-		if err != nil {
-			return nil, err
-		}
-
-		cert, err := tls.X509KeyPair(crt, key)
-		if err != nil {
-			return nil, err
+		if config.ClusterCertificate == nil {
+			return nil, errors.New("a certificate is required to connect to " + nodeUrl + " unless unsecured is set")
 		}
-
-		x509cert, err := x509.ParseCertificate(cert.Certificate[0])
+		var err error
+		certificate, err = pfxCertificate(config.ClusterCertificate, "")
 		if err != nil {
 			return nil, err
 		}
-		store.TrustStore = x509cert
-		store.Certificate = &cert
 	}
-
-	if err := store.Initialize(); err != nil {
-		return nil, err
+	create := func() (*ravendb.DocumentStore, error) {
+		return newStore([]string{nodeUrl}, config.HealthcheckDatabase, certificate, config.TLS)
 	}
-
-	return store, nil
+	if config.stores == nil {
+		return create()
+	}
+	return config.stores.get(storeKey([]string{nodeUrl}, config.HealthcheckDatabase, certificate, config.TLS), create)
 }
 
 func (sc *ServerConfig) addNodesToCluster(store *ravendb.DocumentStore) error {
-	clusterTopology, err := sc.getClusterTopology(store)
+	// a node that isn't part of a cluster yet answers with an AllTopologyNodesDownError, which isn't retried so a
+	// new cluster doesn't wait through the whole backoff
 	var errAllDown *ravendb.AllTopologyNodesDownError
+	clusterTopology, err := sc.readClusterTopology(store, func(store *ravendb.DocumentStore, operation ravendb.IServerOperation) error {
+		err := store.Maintenance().Server().Send(operation)
+		if err == nil || errors.As(err, &errAllDown) {
+			return err
+		}
+		return executeWithRetries(store, operation)
+	})
 	if errors.As(err, &errAllDown) {
 		for i := 1; i < len(sc.Url.List); i++ {
-			err = addNodeToCluster(store, sc.Url.List[i])
+			err = sc.addNodeToCluster(store, sc.Url.List[i])
 			if err != nil {
 				return err
 			}
@@ -641,47 +1175,68 @@ func (sc *ServerConfig) addNodesToCluster(store *ravendb.DocumentStore) error {
 				Node: nodeUrl,
 				Tag:  tag,
 			})
+			sc.forgetClusterTopology(store)
 			if err != nil {
 				return err
 			}
 		}
 	}
 
+	var nodes []string
 	for _, node := range sc.Url.List {
-		if containsValue(clusterTopology.Topology.AllNodes, node) {
-			continue
-		} else {
-			err = addNodeToCluster(store, node)
+		if containsValue(clusterTopology.Topology.AllNodes, node) == false {
+			nodes = append(nodes, node)
+		}
+	}
+	return sc.addNodes(store, nodes)
+}
+
+// addNodes adds the nodes to the cluster, concurrently when sc.Parallel.ClusterJoin is set.
+func (sc *ServerConfig) addNodes(store *ravendb.DocumentStore, nodes []string) error {
+	if sc.Parallel.ClusterJoin == false {
+		for _, node := range nodes {
+			err := sc.addNodeToCluster(store, node)
 			if err != nil {
 				return err
 			}
 		}
+		return nil
 	}
 
-	return nil
-}
-
-func (sc *ServerConfig) purgeRavenDbInstance(publicIP string) error {
-	var stdoutBuf bytes.Buffer
-	signer, err := ssh.ParsePrivateKey(sc.SSH.Pem)
-	if err != nil {
-		return err
+	var wg sync.WaitGroup
+	errorsChannel := make(chan error, len(nodes))
+	for _, node := range nodes {
+		wg.Add(1)
+		go func(copyOfNode string) {
+			defer wg.Done()
+			err := sc.addNodeToCluster(store, copyOfNode)
+			if err != nil {
+				errorsChannel <- err
+			}
+		}(node)
 	}
+	wg.Wait()
+	close(errorsChannel)
 
-	authConfig := &ssh.ClientConfig{
-		User:            sc.SSH.User,
-		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	var result error
+	for err := range errorsChannel {
+		result = multierror.Append(result, err)
 	}
-	conn, err := ssh.Dial("tcp", net.JoinHostPort(publicIP, fmt.Sprint(sc.SSH.getPort())), authConfig)
+	return result
+}
+
+func (sc *ServerConfig) purgeRavenDbInstance(publicIP string) error {
+	stdoutBuf := newNodeLog(publicIP)
+	conn, err := sc.dialHost(publicIP, 0)
 	if err != nil {
 		return err
 	}
 	defer conn.Close()
 
-	err = sc.execute(publicIP, []string{
-		"sudo apt-get -y purge ravendb",
-	}, "", &stdoutBuf, conn)
+	family, err := detectOsFamily(conn)
+	if err == nil {
+		err = sc.execute(publicIP, purgeCommands(family), "", stdoutBuf, conn)
+	}
 
 	if err != nil {
 		stdoutBuf.WriteString("Failed to delete ravendb instance. Host machine ip: " + publicIP + "\n")
@@ -689,7 +1244,7 @@ func (sc *ServerConfig) purgeRavenDbInstance(publicIP string) error {
 		stdoutBuf.WriteString("Deleted successfully ravendb instance. Host machine ip " + publicIP + "\n")
 
 	}
-	log.Println(stdoutBuf.String())
+	stdoutBuf.flush()
 	return err
 }
 
@@ -716,6 +1271,9 @@ func (sc *ServerConfig) RemoveRavenDbInstances() diag.Diagnostics {
 	for err := range errorsChanel {
 		result = multierror.Append(result, err)
 	}
+	if err := sc.Consul.deregister(sc); err != nil {
+		result = multierror.Append(result, err)
+	}
 	if result != nil {
 		return diag.FromErr(fmt.Errorf(errorDelete, result.Error()))
 	} else {
@@ -724,22 +1282,57 @@ func (sc *ServerConfig) RemoveRavenDbInstances() diag.Diagnostics {
 
 }
 
+// distributeHealthcheckSecret has the server generate the encryption key of the healthcheck database and
+// store it on every node, as an encrypted database can only be created on nodes that have its key.
+func (sc *ServerConfig) distributeHealthcheckSecret(store *ravendb.DocumentStore) error {
+	if sc.Unsecured {
+		return errors.New("the healthcheck database can only be encrypted on a secured cluster")
+	}
+	topology, err := sc.getClusterTopology(store)
+	if err != nil {
+		return err
+	}
+	var nodes []string
+	for tag := range topology.Topology.Members {
+		nodes = append(nodes, tag)
+	}
+
+	secret := internal_operations.OperationGenerateSecret{}
+	err = executeWithRetries(store, &secret)
+	if err != nil {
+		return err
+	}
+	return executeWithRetries(store, &internal_operations.OperationDistributeSecret{
+		Database: sc.HealthcheckDatabase,
+		Key:      secret.Result,
+		Nodes:    nodes,
+	})
+}
+
 func (sc *ServerConfig) createDb(store *ravendb.DocumentStore) error {
 	for i := 0; i < NUMBER_OF_RETRIES; i++ {
-		topology, err := sc.getClusterTopology(store)
+		topology, err := sc.refreshClusterTopology(store)
 		if err != nil {
 			return err
 		}
 		if len(topology.Topology.Members) != len(sc.Url.List) {
-			time.Sleep(time.Second * 5)
+			time.Sleep(backoff(i))
 		} else {
 			break
 		}
 	}
+	if sc.Healthcheck.Encrypted {
+		err := sc.distributeHealthcheckSecret(store)
+		if err != nil {
+			return err
+		}
+	}
+
 	err := executeWithRetries(store,
 		ravendb.NewCreateDatabaseOperation(&ravendb.DatabaseRecord{
 			DatabaseName: sc.HealthcheckDatabase,
-		}, len(sc.Hosts)))
+			Encrypted:    sc.Healthcheck.Encrypted,
+		}, sc.Healthcheck.ReplicationFactor))
 
 	if err != nil && reflect.TypeOf(err) != reflect.TypeOf(&ravendb.ConcurrencyError{}) {
 		return err
@@ -747,22 +1340,12 @@ func (sc *ServerConfig) createDb(store *ravendb.DocumentStore) error {
 	return nil
 }
 
-func addNodeToCluster(store *ravendb.DocumentStore, node string) error {
-	parse, err := url.Parse(node)
-	if err != nil {
-		return err
-	}
-	hostName := strings.Split(parse.Host, ".")
-	tag := hostName[0]
-	match, err := regexp.MatchString("[A-Za-z]{1,4}", tag)
+func (sc *ServerConfig) addNodeToCluster(store *ravendb.DocumentStore, node string) error {
+	tag, err := sc.nodeTag(node)
 	if err != nil {
 		return err
 	}
-	if !match {
-		tag = ""
-	} else {
-		tag = strings.ToUpper(tag)
-	}
+	defer sc.forgetClusterTopology(store)
 	return executeWithRetries(store, &operations.OperationAddClusterNode{
 		Url: node,
 		Tag: tag,
@@ -771,44 +1354,68 @@ func addNodeToCluster(store *ravendb.DocumentStore, node string) error {
 }
 
 func executeWithRetriesMaintenanceOperations(store *ravendb.DocumentStore, operation ravendb.IVoidMaintenanceOperation) error {
+	var errDatabaseDoesNotExist *ravendb.DatabaseDoesNotExistError
 	var err error
 	for i := 0; i < NUMBER_OF_RETRIES; i++ {
 		err = store.Maintenance().Send(operation)
-		if err == nil {
-			return nil
+		if err == nil || errors.As(err, &errDatabaseDoesNotExist) {
+			return err
+		}
+		if i < NUMBER_OF_RETRIES-1 {
+			// we may need to wait a bit because adding a node to the cluster may move things around
+			time.Sleep(backoff(i))
 		}
-		// we may need to wait a bit because adding a node to the cluster may move things around
-		time.Sleep(time.Second * 5)
 	}
 	return err
 }
 
 func executeWithRetries(store *ravendb.DocumentStore, operation ravendb.IServerOperation) error {
-	var errNoLeader *ravendb.NoLeaderError
 	var err error
 	for i := 0; i < NUMBER_OF_RETRIES; i++ {
 		err = store.Maintenance().Server().Send(operation)
-		if err == nil {
-			return nil
-		}
-
-		if !errors.As(err, &errNoLeader) && !errors.As(err, &errNoLeader) {
+		if err == nil || !isRetryableError(err) || databaseAlreadyExists(operation, err) || staleDatabaseRecord(operation, err) {
 			return err
 		}
-		// we may need to wait a bit because adding a node to the cluster may move things around
-		time.Sleep(time.Second * 5)
+		if i < NUMBER_OF_RETRIES-1 {
+			// we may need to wait a bit because adding a node to the cluster may move things around
+			time.Sleep(backoff(i))
+		}
 	}
 	return err
 }
 
-func readFileContents(path string, stdoutBuf bytes.Buffer, conn *ssh.Client) ([]byte, error) {
-	session, err := conn.NewSession()
-	if err != nil {
-		return nil, err
+// isRetryableError reports whether err is caused by transient cluster churn (elections,
+// nodes restarting, concurrent topology changes) and the operation is worth sending again.
+func isRetryableError(err error) bool {
+	var errNoLeader *ravendb.NoLeaderError
+	var errAllDown *ravendb.AllTopologyNodesDownError
+	var errConcurrency *ravendb.ConcurrencyError
+	return errors.As(err, &errNoLeader) || errors.As(err, &errAllDown) || errors.As(err, &errConcurrency)
+}
+
+// databaseAlreadyExists reports whether err answers the creation of a database that already exists. The server
+// reports it with a ConcurrencyError, which no retry is going to change.
+func databaseAlreadyExists(operation ravendb.IServerOperation, err error) bool {
+	var errConcurrency *ravendb.ConcurrencyError
+	_, create := operation.(*ravendb.CreateDatabaseOperation)
+	return create && errors.As(err, &errConcurrency)
+}
+
+// backoff returns the delay before the next retry. The delay grows exponentially with the
+// attempt number up to RETRY_MAX_DELAY, and half of it is randomized so nodes retrying in
+// parallel don't hit the cluster at the same moment.
+func backoff(attempt int) time.Duration {
+	delay := RETRY_MAX_DELAY
+	if attempt < 16 && RETRY_BASE_DELAY<<uint(attempt) < RETRY_MAX_DELAY {
+		delay = RETRY_BASE_DELAY << uint(attempt)
 	}
-	defer session.Close()
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func readFileContents(path string, stdoutBuf *nodeLog, conn Transport) ([]byte, error) {
 	stdoutBuf.WriteString("sudo cat " + path + "\n")
-	out, err := session.CombinedOutput("sudo cat " + path)
+	out, err := runCommand(conn, "sudo cat "+path)
 	if err != nil {
 		stdoutBuf.Write(out)
 		return nil, &DeployError{
@@ -828,6 +1435,16 @@ func contains(s []string, str string) bool {
 	return false
 }
 
+// containsAny reports whether s contains any of values.
+func containsAny(s []string, values []string) bool {
+	for _, v := range values {
+		if contains(s, v) {
+			return true
+		}
+	}
+	return false
+}
+
 func containsValue(m map[string]string, v string) bool {
 	for _, x := range m {
 		if x == v {
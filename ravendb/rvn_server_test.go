@@ -0,0 +1,175 @@
+package ravendb
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/ravendb/ravendb-go-client"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBackoffStaysWithinBounds(t *testing.T) {
+	expected := map[int]time.Duration{
+		0:  2 * time.Second,
+		1:  4 * time.Second,
+		2:  8 * time.Second,
+		3:  16 * time.Second,
+		4:  30 * time.Second,
+		5:  30 * time.Second,
+		16: 30 * time.Second,
+		63: 30 * time.Second,
+	}
+	for attempt, limit := range expected {
+		for i := 0; i < 20; i++ {
+			delay := backoff(attempt)
+			if delay < limit/2 || delay > limit {
+				t.Fatalf("attempt %d: delay %s is outside of [%s, %s]", attempt, delay, limit/2, limit)
+			}
+		}
+	}
+}
+
+func TestIsRetryableError(t *testing.T) {
+	if !isRetryableError(&ravendb.NoLeaderError{}) {
+		t.Error("expected NoLeaderError to be retryable")
+	}
+	if !isRetryableError(&ravendb.AllTopologyNodesDownError{}) {
+		t.Error("expected AllTopologyNodesDownError to be retryable")
+	}
+	if !isRetryableError(&ravendb.ConcurrencyError{}) {
+		t.Error("expected ConcurrencyError to be retryable")
+	}
+	if isRetryableError(errors.New("unauthorized")) {
+		t.Error("expected a generic error not to be retryable")
+	}
+}
+
+func TestDatabaseAlreadyExists(t *testing.T) {
+	create := ravendb.NewCreateDatabaseOperation(&ravendb.DatabaseRecord{DatabaseName: "Orders"}, 1)
+	if !databaseAlreadyExists(create, &ravendb.ConcurrencyError{}) {
+		t.Error("expected a ConcurrencyError answering a database creation to mean the database exists")
+	}
+	if databaseAlreadyExists(create, &ravendb.NoLeaderError{}) {
+		t.Error("expected a NoLeaderError answering a database creation to be retried")
+	}
+	if databaseAlreadyExists(ravendb.NewDeleteDatabasesOperation("Orders", true), &ravendb.ConcurrencyError{}) {
+		t.Error("expected a ConcurrencyError answering another operation to be retried")
+	}
+}
+
+func TestNodeTag(t *testing.T) {
+	sc := ServerConfig{NodeTags: []string{"", "WTCH"}}
+	sc.Url.List = []string{"https://a.example.com", "https://b.example.com"}
+	expected := map[string]string{
+		"https://a.example.com":   "A",
+		"https://b.example.com":   "WTCH",
+		"https://10.0.0.3:8080":   "",
+		"https://db3.example.com": "DB3",
+	}
+	for nodeUrl, tag := range expected {
+		actual, err := sc.nodeTag(nodeUrl)
+		if err != nil || actual != tag {
+			t.Errorf("%s: expected tag %q, got %q (%v)", nodeUrl, tag, actual, err)
+		}
+	}
+
+	if err := sc.checkNodeTags(map[string]string{"A": "https://a.example.com", "B": "https://b.example.com"}); err == nil {
+		t.Error("expected the node joined as B to conflict with its WTCH tag")
+	}
+}
+
+func TestSettingsWarnings(t *testing.T) {
+	sc := ServerConfig{Settings: map[string]interface{}{
+		"Indexing.MapBatchSize":           "1024",
+		"security.unsecuredaccessallowed": "PublicNetwork",
+		"Indexing.MapBatchSise":           "1024",
+		"Indexing.SomeNewerSetting":       "true",
+	}}
+	diags := settingsWarnings(sc)
+	if len(diags) != 2 {
+		t.Fatalf("expected 2 warnings, got %v", diags)
+	}
+	for _, d := range diags {
+		if d.Severity != diag.Warning {
+			t.Errorf("expected %q to be a warning", d.Summary)
+		}
+		if strings.Contains(d.Summary, "Indexing.MapBatchSize") || strings.Contains(d.Summary, "Indexing.SomeNewerSetting") {
+			t.Errorf("expected no warning for a known setting or one unlike any, got %q", d.Summary)
+		}
+		if strings.Contains(d.Summary, "Indexing.MapBatchSise") && !strings.Contains(d.Detail, "Indexing.MapBatchSize") {
+			t.Errorf("expected the typo to suggest Indexing.MapBatchSize, got %q", d.Detail)
+		}
+	}
+}
+
+func TestInstalledHostsAreRecordedOnce(t *testing.T) {
+	var installed installedHosts
+	for _, host := range []string{"10.0.0.2", "10.0.0.1", "10.0.0.2", "10.0.0.2"} {
+		installed.add(host)
+	}
+	hosts := installed.list()
+	if len(hosts) != 2 || hosts[0] != "10.0.0.1" || hosts[1] != "10.0.0.2" {
+		t.Errorf("expected each host once, got %v", hosts)
+	}
+}
+
+func TestPackageIsDownloadedOnceToAFile(t *testing.T) {
+	var downloads int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&downloads, 1)
+		io.WriteString(w, "package")
+	}))
+	defer server.Close()
+
+	var cache packageCache
+	var wg sync.WaitGroup
+	files := make([]string, 3)
+	for i := range files {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			files[i], _ = cache.get(server.URL+"/ravendb.deb", "")
+		}(i)
+	}
+	wg.Wait()
+
+	if downloads != 1 {
+		t.Errorf("expected a single download, got %d", downloads)
+	}
+	content, err := ioutil.ReadFile(files[0])
+	if err != nil || string(content) != "package" || files[1] != files[0] || files[2] != files[0] {
+		t.Fatalf("expected every host to get the downloaded file, got %v: %v", files, err)
+	}
+	cache.close()
+	if _, err := os.Stat(files[0]); !os.IsNotExist(err) {
+		t.Errorf("expected close to remove %s", files[0])
+	}
+}
+
+func TestTrafficWatchFiltersAreVerified(t *testing.T) {
+	tw := &TrafficWatch{Mode: "ToLogFile", Databases: []string{"Orders", "Users"}, StatusCodes: []int{500, 404}}
+
+	var settings map[string]interface{}
+	err := json.Unmarshal([]byte(`{"TrafficWatch.Mode": "ToLogFile", "TrafficWatch.Databases": "users;orders", "TrafficWatch.StatusCodes": [404, 500]}`), &settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if warnings := tw.verify("10.0.0.1", settings); len(warnings) != 0 {
+		t.Errorf("expected the filters to match, got %v", warnings)
+	}
+
+	settings["TrafficWatch.StatusCodes"] = []interface{}{float64(500)}
+	warnings := tw.verify("10.0.0.1", settings)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "status codes 500 instead of 404, 500") {
+		t.Errorf("expected a warning about the status codes, got %v", warnings)
+	}
+}
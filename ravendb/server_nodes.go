@@ -0,0 +1,235 @@
+package ravendb
+
+import (
+	"encoding/base64"
+	"errors"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// nodeBlockSchema declares the nodes of ravendb_server one by one, as an alternative to the hosts and url.list
+// lists. The settings.json of every node is derived from its block.
+func nodeBlockSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MinItems:    1,
+		Description: "The nodes of the cluster, as an alternative to hosts and url.list.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"host": {
+					Type:         schema.TypeString,
+					Required:     true,
+					Description:  "The ip address or hostname terraform connects to the node with.",
+					ValidateFunc: validateHost,
+				},
+				"public_url": {
+					Type:         schema.TypeString,
+					Required:     true,
+					Description:  "The url the node is reached at, its PublicServerUrl.",
+					ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				},
+				"private_ip": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Description:  "The private ip address of the node, written to /etc/hosts of its peers when peer_hosts is set.",
+					ValidateFunc: validation.IsIPAddress,
+				},
+				"certificate": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Sensitive:    true,
+					Description:  "The server certificate (pfx) of this node, when the nodes don't share the cluster certificate.",
+					ValidateFunc: validation.StringIsBase64,
+				},
+				"arch": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The architecture of this node - amd64, arm64, arm32 - overriding package.arch, for clusters mixing architectures.",
+				},
+				"tag": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Description:  "The tag of the node in the cluster, derived from the hostname of public_url when unset. The first node keeps the tag it got when it created the cluster, A.",
+					ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[A-Z]{1,4}$`), "expected 1 to 4 upper case letters"),
+				},
+			},
+		},
+	}
+}
+
+// hostnamePattern matches a DNS hostname, e.g. db-a.example.com.
+var hostnamePattern = regexp.MustCompile(`^([A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?\.)*[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?\.?$`)
+
+// validateHost accepts the ip address or the hostname of a host.
+var validateHost = validation.Any(
+	validation.IsIPAddress,
+	validation.All(
+		validation.StringLenBetween(1, 253),
+		validation.StringMatch(hostnamePattern, "expected an ip address or a hostname"),
+	),
+)
+
+// hostAddresses returns the ip addresses of host, resolving it when it is a hostname.
+func hostAddresses(host string) ([]string, error) {
+	if net.ParseIP(host) != nil {
+		return []string{host}, nil
+	}
+	return net.LookupHost(host)
+}
+
+// nodeLists returns the hosts and urls of the nodes, from the node blocks or from the hosts and url.list
+// attributes. get is the Get of a ResourceData or a ResourceDiff.
+func nodeLists(get func(string) interface{}) ([]string, []string, error) {
+	var hosts, urls []string
+	for _, host := range get("hosts").([]interface{}) {
+		hosts = append(hosts, host.(string))
+	}
+	for _, v := range get("url").(*schema.Set).List() {
+		for _, u := range v.(map[string]interface{})["list"].([]interface{}) {
+			urls = append(urls, u.(string))
+		}
+	}
+
+	nodes := get("node").([]interface{})
+	if len(nodes) == 0 {
+		if len(urls) == 0 && get("quickstart").(bool) {
+			urls = quickstartUrls(hosts, get("url").(*schema.Set))
+		}
+		if len(hosts) == 0 || len(urls) == 0 {
+			return nil, nil, errors.New("either node blocks or hosts and url.list are required")
+		}
+		return hosts, urls, nil
+	}
+	if len(hosts) > 0 || len(urls) > 0 {
+		return nil, nil, errors.New("node blocks can't be used together with hosts or url.list")
+	}
+	for _, v := range nodes {
+		node := v.(map[string]interface{})
+		hosts = append(hosts, node["host"].(string))
+		urls = append(urls, node["public_url"].(string))
+	}
+	return hosts, urls, nil
+}
+
+// quickstartUrls returns the urls of the nodes of a quickstart cluster: the hosts themselves, over http on the
+// http_port of the url block or the default unsecured port.
+func quickstartUrls(hosts []string, urlSet *schema.Set) []string {
+	port := DEFAULT_USECURED_RAVENDB_HTTP_PORT
+	for _, v := range urlSet.List() {
+		if httpPort := v.(map[string]interface{})["http_port"].(int); httpPort != 0 {
+			port = httpPort
+		}
+	}
+	urls := make([]string, len(hosts))
+	for i, host := range hosts {
+		urls[i] = "http://" + net.JoinHostPort(host, strconv.Itoa(port))
+	}
+	return urls
+}
+
+// parseNodeBlocks reads the per node certificates, architectures, tags and private ip addresses of the node blocks.
+func (sc *ServerConfig) parseNodeBlocks(d *schema.ResourceData) error {
+	nodes := d.Get("node").([]interface{})
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	sc.NodeCertificates = make([][]byte, len(nodes))
+	sc.NodeArchs = make([]string, len(nodes))
+	sc.NodeTags = make([]string, len(nodes))
+	tags := map[string]bool{}
+	var privateIps []string
+	for i, v := range nodes {
+		node := v.(map[string]interface{})
+		if certificate := node["certificate"].(string); certificate != "" {
+			cert, err := base64.StdEncoding.DecodeString(certificate)
+			if err != nil {
+				return err
+			}
+			sc.NodeCertificates[i] = cert
+		}
+		if arch := node["arch"].(string); arch != "" {
+			sc.NodeArchs[i] = packageArch(arch)
+			if sc.Package.Version != "" {
+				if err := sc.nodePackage(i).checkUrl(); err != nil {
+					return err
+				}
+			}
+		}
+		if tag := node["tag"].(string); tag != "" {
+			if tags[tag] {
+				return errors.New("the tag " + tag + " is set on more than one node block")
+			}
+			tags[tag] = true
+			sc.NodeTags[i] = tag
+		}
+		if privateIp := node["private_ip"].(string); privateIp != "" {
+			privateIps = append(privateIps, privateIp)
+		}
+	}
+
+	if sc.PeerHosts != nil && len(sc.PeerHosts.PrivateIps) == 0 && len(privateIps) > 0 {
+		if len(privateIps) != len(nodes) {
+			return errors.New("private_ip must be set on all the node blocks or on none of them")
+		}
+		sc.PeerHosts.PrivateIps = privateIps
+	}
+	return nil
+}
+
+// nodeCertificate returns the server certificate of the node at index.
+func (sc *ServerConfig) nodeCertificate(index int) []byte {
+	if index < len(sc.NodeCertificates) && sc.NodeCertificates[index] != nil {
+		return sc.NodeCertificates[index]
+	}
+	return sc.ClusterCertificate
+}
+
+// nodePackage returns the package installed on the node at index, in the architecture of its node block if it
+// overrides package.arch.
+func (sc *ServerConfig) nodePackage(index int) Package {
+	p := sc.Package
+	if index < len(sc.NodeArchs) && sc.NodeArchs[index] != "" {
+		p.Arch = sc.NodeArchs[index]
+	}
+	return p
+}
+
+// nodeTag returns the tag the node at nodeUrl joins the cluster with: the tag of its node block, or else the
+// first label of its hostname when it looks like a tag. An empty tag lets the cluster choose.
+func (sc *ServerConfig) nodeTag(nodeUrl string) (string, error) {
+	for index, u := range sc.Url.List {
+		if u == nodeUrl && index < len(sc.NodeTags) && sc.NodeTags[index] != "" {
+			return sc.NodeTags[index], nil
+		}
+	}
+	parse, err := url.Parse(nodeUrl)
+	if err != nil {
+		return "", err
+	}
+	tag := strings.Split(parse.Host, ".")[0]
+	match, err := regexp.MatchString("[A-Za-z]{1,4}", tag)
+	if err != nil || !match {
+		return "", err
+	}
+	return strings.ToUpper(tag), nil
+}
+
+// checkNodeTags fails when a node is in the cluster, given as its nodes by tag, under another tag than the one
+// of its node block. Tags don't change once a node joined, the node has to leave the cluster first.
+func (sc *ServerConfig) checkNodeTags(nodes map[string]string) error {
+	for tag, nodeUrl := range nodes {
+		for index, u := range sc.Url.List {
+			if u == nodeUrl && index < len(sc.NodeTags) && sc.NodeTags[index] != "" && sc.NodeTags[index] != tag {
+				return errors.New("the node " + nodeUrl + " is " + tag + " in the cluster, not " + sc.NodeTags[index] + " as set in its node block")
+			}
+		}
+	}
+	return nil
+}
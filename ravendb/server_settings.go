@@ -0,0 +1,852 @@
+package ravendb
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const PROMETHEUS_METRICS_PATH = "/admin/monitoring/v1/prometheus"
+
+const (
+	SETTINGS_MERGE                  string = "merge"
+	SETTINGS_MERGE_REPLACE          string = "replace"
+	SETTINGS_MERGE_PRESERVE_UNKNOWN string = "preserve_unknown"
+)
+
+type Logging struct {
+	Mode            string
+	Path            string
+	MaxFileSizeInMb int
+	RetentionInHrs  int
+	UseUtcTime      bool
+}
+
+type TrafficWatch struct {
+	Mode        string
+	Databases   []string
+	StatusCodes []int
+	HttpMethods []string
+}
+
+type ClusterObserver struct {
+	SupervisorSamplePeriodInMs int
+	StabilizationTimeInSec     int
+	MoveToRehabGraceTimeInSec  int
+	AddReplicaTimeoutInSec     int
+}
+
+type PostgreSql struct {
+	Port  int
+	Users []PostgreSqlUser
+}
+
+type PostgreSqlUser struct {
+	Database string
+	Username string
+	Password string
+}
+
+type Monitoring struct {
+	SnmpEnabled          bool
+	SnmpPort             int
+	SnmpCommunity        string
+	PrometheusTargetPath string
+}
+
+func monitoringSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"snmp_enabled": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Description: "Whatever to enable the SNMP monitoring endpoint on every node.",
+				},
+				"snmp_port": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      161,
+					ValidateFunc: validation.IsPortNumber,
+				},
+				"snmp_community": {
+					Type:     schema.TypeString,
+					Optional: true,
+					Default:  "ravendb",
+				},
+				"prometheus_target_path": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Absolute path on every node to write a Prometheus file_sd target pointing at the node metrics endpoint.",
+				},
+			},
+		},
+	}
+}
+
+func parseMonitoring(d *schema.ResourceData) Monitoring {
+	var monitoring Monitoring
+	for _, v := range d.Get("monitoring").(*schema.Set).List() {
+		value := v.(map[string]interface{})
+		monitoring.SnmpEnabled = value["snmp_enabled"].(bool)
+		monitoring.SnmpPort = value["snmp_port"].(int)
+		monitoring.SnmpCommunity = value["snmp_community"].(string)
+		monitoring.PrometheusTargetPath = value["prometheus_target_path"].(string)
+	}
+	return monitoring
+}
+
+func (m *Monitoring) applyTo(settings map[string]interface{}) {
+	if m.SnmpEnabled == false {
+		return
+	}
+	settings["Monitoring.Snmp.Enabled"] = true
+	settings["Monitoring.Snmp.Port"] = m.SnmpPort
+	settings["Monitoring.Snmp.Community"] = m.SnmpCommunity
+}
+
+// prometheusTarget renders a file_sd_configs entry for the node, so a Prometheus server
+// watching the file scrapes the node without any extra relabeling.
+func (m *Monitoring) prometheusTarget(httpUrl string, host string) ([]byte, error) {
+	u, err := url.Parse(httpUrl)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent([]map[string]interface{}{
+		{
+			"targets": []string{u.Host},
+			"labels": map[string]string{
+				"__scheme__":       u.Scheme,
+				"__metrics_path__": PROMETHEUS_METRICS_PATH,
+				"host":             host,
+			},
+		},
+	}, "", "\t")
+}
+
+func loggingSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"mode": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "Operations",
+					Description:  "The server log level - None, Operations, Information.",
+					ValidateFunc: validation.StringInSlice([]string{"None", "Operations", "Information"}, false),
+				},
+				"path": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The directory the server writes its log files to.",
+				},
+				"max_file_size_mb": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"retention_hours": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"use_utc": {
+					Type:     schema.TypeBool,
+					Optional: true,
+				},
+			},
+		},
+	}
+}
+
+func parseLogging(d *schema.ResourceData) *Logging {
+	for _, v := range d.Get("logging").(*schema.Set).List() {
+		value := v.(map[string]interface{})
+		return &Logging{
+			Mode:            value["mode"].(string),
+			Path:            value["path"].(string),
+			MaxFileSizeInMb: value["max_file_size_mb"].(int),
+			RetentionInHrs:  value["retention_hours"].(int),
+			UseUtcTime:      value["use_utc"].(bool),
+		}
+	}
+	return nil
+}
+
+func (l *Logging) applyTo(settings map[string]interface{}) {
+	if l == nil {
+		return
+	}
+	settings["Logs.Mode"] = l.Mode
+	settings["Logs.UseUtcTime"] = l.UseUtcTime
+	if l.Path != "" {
+		settings["Logs.Path"] = l.Path
+	}
+	if l.MaxFileSizeInMb != 0 {
+		settings["Logs.MaxFileSizeInMb"] = l.MaxFileSizeInMb
+	}
+	if l.RetentionInHrs != 0 {
+		settings["Logs.RetentionTimeInHrs"] = l.RetentionInHrs
+	}
+}
+
+func trafficWatchSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"mode": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "ToLogFile",
+					Description:  "Traffic watch mode - Off, ToLogFile.",
+					ValidateFunc: validation.StringInSlice([]string{"Off", "ToLogFile"}, false),
+				},
+				"databases": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					Description: "Only log traffic of these databases. All databases are logged when empty.",
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+				"status_codes": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					Description: "Only log requests that completed with these HTTP status codes.",
+					Elem: &schema.Schema{
+						Type:         schema.TypeInt,
+						ValidateFunc: validation.IntBetween(100, 599),
+					},
+				},
+				"http_methods": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Schema{
+						Type:         schema.TypeString,
+						ValidateFunc: validation.StringInSlice([]string{"GET", "POST", "PUT", "DELETE", "HEAD", "PATCH", "OPTIONS"}, false),
+					},
+				},
+			},
+		},
+	}
+}
+
+func parseTrafficWatch(d *schema.ResourceData) *TrafficWatch {
+	for _, v := range d.Get("traffic_watch").(*schema.Set).List() {
+		value := v.(map[string]interface{})
+		tw := &TrafficWatch{
+			Mode: value["mode"].(string),
+		}
+		for _, db := range value["databases"].([]interface{}) {
+			tw.Databases = append(tw.Databases, db.(string))
+		}
+		for _, code := range value["status_codes"].([]interface{}) {
+			tw.StatusCodes = append(tw.StatusCodes, code.(int))
+		}
+		for _, method := range value["http_methods"].([]interface{}) {
+			tw.HttpMethods = append(tw.HttpMethods, method.(string))
+		}
+		return tw
+	}
+	return nil
+}
+
+func (tw *TrafficWatch) applyTo(settings map[string]interface{}) {
+	if tw == nil {
+		return
+	}
+	settings["TrafficWatch.Mode"] = tw.Mode
+	if len(tw.Databases) > 0 {
+		settings["TrafficWatch.Databases"] = tw.Databases
+	}
+	if len(tw.StatusCodes) > 0 {
+		settings["TrafficWatch.StatusCodes"] = tw.StatusCodes
+	}
+	if len(tw.HttpMethods) > 0 {
+		settings["TrafficWatch.HttpMethods"] = tw.HttpMethods
+	}
+}
+
+// verify compares the traffic watch settings read back from a node, as parsed from settings.json, with the
+// configured ones. The filters are compared as sets, whether the node has them as an array or as a separated string.
+func (tw *TrafficWatch) verify(host string, settings map[string]interface{}) []string {
+	if tw == nil {
+		return nil
+	}
+	var warnings []string
+	if mode, ok := settings["TrafficWatch.Mode"]; ok == false || mode != tw.Mode {
+		warnings = append(warnings, "Traffic watch on "+host+" is not running in "+tw.Mode+" mode")
+	}
+
+	statusCodes := make([]string, len(tw.StatusCodes))
+	for i, code := range tw.StatusCodes {
+		statusCodes[i] = strconv.Itoa(code)
+	}
+	filters := []struct {
+		key      string
+		name     string
+		expected []string
+	}{
+		{"TrafficWatch.Databases", "databases", tw.Databases},
+		{"TrafficWatch.StatusCodes", "status codes", statusCodes},
+		{"TrafficWatch.HttpMethods", "HTTP methods", tw.HttpMethods},
+	}
+	for _, filter := range filters {
+		actual := settingValues(settings[filter.key])
+		expected := normalizedValues(filter.expected)
+		if strings.Join(actual, ",") != strings.Join(expected, ",") {
+			warnings = append(warnings, "Traffic watch on "+host+" logs the "+filter.name+" "+describeFilter(actual)+" instead of "+describeFilter(expected))
+		}
+	}
+	return warnings
+}
+
+// settingValues returns the values of a list setting of settings.json, sorted and lower cased, whether it is a JSON
+// array or a string separated with ; or ,.
+func settingValues(setting interface{}) []string {
+	var values []string
+	switch setting := setting.(type) {
+	case nil:
+	case []interface{}:
+		for _, value := range setting {
+			if number, ok := value.(float64); ok {
+				values = append(values, strconv.FormatFloat(number, 'f', -1, 64))
+			} else {
+				values = append(values, fmt.Sprint(value))
+			}
+		}
+	case string:
+		values = strings.FieldsFunc(setting, func(r rune) bool {
+			return r == ';' || r == ','
+		})
+	default:
+		values = []string{fmt.Sprint(setting)}
+	}
+	return normalizedValues(values)
+}
+
+func normalizedValues(values []string) []string {
+	normalized := []string{}
+	for _, value := range values {
+		if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+			normalized = append(normalized, value)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+func describeFilter(values []string) string {
+	if len(values) == 0 {
+		return "(all)"
+	}
+	return strings.Join(values, ", ")
+}
+
+func clusterObserverSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"supervisor_sample_period_ms": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Description:  "How often the cluster observer samples the nodes and takes decisions.",
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"stabilization_time_sec": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Description:  "How long the cluster observer waits after a leader change before taking decisions.",
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"move_to_rehab_grace_time_sec": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Description:  "How long a node may be unresponsive before its databases are moved to rehab.",
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"add_replica_timeout_sec": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Description:  "How long to wait before adding a replacement replica for a node in rehab.",
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+func parseClusterObserver(d *schema.ResourceData) *ClusterObserver {
+	for _, v := range d.Get("cluster_observer").(*schema.Set).List() {
+		value := v.(map[string]interface{})
+		return &ClusterObserver{
+			SupervisorSamplePeriodInMs: value["supervisor_sample_period_ms"].(int),
+			StabilizationTimeInSec:     value["stabilization_time_sec"].(int),
+			MoveToRehabGraceTimeInSec:  value["move_to_rehab_grace_time_sec"].(int),
+			AddReplicaTimeoutInSec:     value["add_replica_timeout_sec"].(int),
+		}
+	}
+	return nil
+}
+
+func (co *ClusterObserver) applyTo(settings map[string]interface{}) {
+	if co == nil {
+		return
+	}
+	values := map[string]int{
+		"Cluster.SupervisorSamplePeriodInMs": co.SupervisorSamplePeriodInMs,
+		"Cluster.StabilizationTimeInSec":     co.StabilizationTimeInSec,
+		"Cluster.MoveToRehabGraceTimeInSec":  co.MoveToRehabGraceTimeInSec,
+		"Cluster.AddReplicaTimeoutInSec":     co.AddReplicaTimeoutInSec,
+	}
+	for key, value := range values {
+		if value != 0 {
+			settings[key] = value
+		}
+	}
+}
+
+// offlineLicenseSettings keep an air-gapped server from reaching out to the RavenDB license server.
+var offlineLicenseSettings = map[string]interface{}{
+	"License.DisableAutoUpdate":          true,
+	"License.DisableAutoUpdateFromApi":   true,
+	"License.DisableLicenseSupportCheck": true,
+	"License.SkipLeasingErrorsLogging":   true,
+}
+
+func postgreSqlSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Enables the PostgreSQL protocol endpoint on every node.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"port": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      5433,
+					ValidateFunc: validation.IsPortNumber,
+				},
+				"user": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"database": {
+								Type:     schema.TypeString,
+								Required: true,
+							},
+							"username": {
+								Type:     schema.TypeString,
+								Required: true,
+							},
+							"password": {
+								Type:      schema.TypeString,
+								Required:  true,
+								Sensitive: true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func parsePostgreSql(d *schema.ResourceData) *PostgreSql {
+	for _, v := range d.Get("postgresql").(*schema.Set).List() {
+		value := v.(map[string]interface{})
+		pg := &PostgreSql{
+			Port: value["port"].(int),
+		}
+		for _, u := range value["user"].([]interface{}) {
+			user := u.(map[string]interface{})
+			pg.Users = append(pg.Users, PostgreSqlUser{
+				Database: user["database"].(string),
+				Username: user["username"].(string),
+				Password: user["password"].(string),
+			})
+		}
+		return pg
+	}
+	return nil
+}
+
+func (pg *PostgreSql) applyTo(settings map[string]interface{}) {
+	if pg == nil {
+		return
+	}
+	settings["Integrations.PostgreSQL.Enabled"] = true
+	settings["Integrations.PostgreSQL.Port"] = pg.Port
+}
+
+// settingsBase returns the settings the provider settings are applied on, given the settings.json read from a node
+// and sc.SettingsMerge.
+func (sc *ServerConfig) settingsBase(current map[string]interface{}) (map[string]interface{}, error) {
+	switch sc.SettingsMerge {
+	case SETTINGS_MERGE_REPLACE:
+		var base map[string]interface{}
+		err := json.Unmarshal([]byte(ravendbDefaultSettings), &base)
+		return base, err
+	case SETTINGS_MERGE_PRESERVE_UNKNOWN:
+		for _, key := range sc.RemovedSettings {
+			delete(current, key)
+		}
+	}
+	return current, nil
+}
+
+// knownSettings are RavenDB server configuration keys settings.json may hold. RavenDB ignores keys it doesn't
+// know, so a mistyped key otherwise goes unnoticed. The list isn't complete for every version, it is only used to
+// suggest the key a near miss was meant to be.
+var knownSettings = []string{
+	"ServerUrl",
+	"ServerUrl.Tcp",
+	"PublicServerUrl",
+	"PublicServerUrl.Tcp",
+	"ExternalIp",
+	"DataDir",
+	"RunInMemory",
+	"Setup.Mode",
+	"AcmeUrl",
+	"ThrowIfAnyIndexCannotBeOpened",
+	"Features.Availability",
+	"Embedded.ParentProcessId",
+	"Studio.Path",
+	"Updates.BackgroundChecksDisabled",
+	"Updates.Channel",
+
+	"Security.UnsecuredAccessAllowed",
+	"Security.Certificate.Path",
+	"Security.Certificate.Password",
+	"Security.Certificate.Load.Exec",
+	"Security.Certificate.Load.Exec.Arguments",
+	"Security.Certificate.Renew.Exec",
+	"Security.Certificate.Renew.Exec.Arguments",
+	"Security.Certificate.Change.Exec",
+	"Security.Certificate.Change.Exec.Arguments",
+	"Security.Certificate.Exec.TimeoutInSec",
+	"Security.Certificate.LetsEncrypt.Email",
+	"Security.Certificate.Validation.KeyUsages",
+	"Security.Certificate.ExpiringThresholdInDays",
+	"Security.MasterKey.Path",
+	"Security.MasterKey.Exec",
+	"Security.MasterKey.Exec.Arguments",
+	"Security.MasterKey.Exec.TimeoutInSec",
+	"Security.AuditLog.FolderPath",
+	"Security.AuditLog.RetentionTimeInHrs",
+	"Security.AuditLog.RetentionSizeInMb",
+	"Security.AuditLog.Compress",
+	"Security.WellKnownCertificates.Admin",
+	"Security.WellKnownIssuers.Admin",
+	"Security.WellKnownIssuerHashes.Admin",
+	"Security.Csrf.Enabled",
+	"Security.Csrf.TrustedOrigins",
+	"Security.Csrf.AdditionalOriginHeaders",
+	"Security.TlsCipherSuites",
+	"Security.DisableHsts",
+	"Security.DisableHttpsRedirection",
+	"Security.DoNotConsiderMemoryLockFailureAsCatastrophicError",
+
+	"License",
+	"License.Path",
+	"License.Eula.Accepted",
+	"License.CanActivate",
+	"License.CanForceUpdate",
+	"License.CanRenew",
+	"License.DisableAutoUpdate",
+	"License.DisableAutoUpdateFromApi",
+	"License.DisableLicenseSupportCheck",
+	"License.SkipLeasingErrorsLogging",
+	"License.ThrowOnInvalidOrMissingLicense",
+
+	"Logs.Path",
+	"Logs.Mode",
+	"Logs.UseUtcTime",
+	"Logs.MaxFileSizeInMb",
+	"Logs.RetentionTimeInHrs",
+	"Logs.RetentionSizeInMb",
+	"Logs.Compress",
+	"Logs.Microsoft.Disable",
+
+	"Http.MinDataRateBytesPerSec",
+	"Http.MinDataRateGracePeriodInSec",
+	"Http.MaxRequestBufferSizeInKb",
+	"Http.MaxRequestLineSizeInKb",
+	"Http.Http2.KeepAlivePingTimeoutInSec",
+	"Http.Http2.KeepAlivePingDelayInSec",
+	"Http.Http2.MaxStreamsPerConnection",
+	"Http.UseResponseCompression",
+	"Http.AllowResponseCompressionOverHttps",
+	"Http.GzipResponseCompressionLevel",
+	"Http.DeflateResponseCompressionLevel",
+	"Http.UseLibuv",
+	"Http.Protocols",
+	"Http.AllowSynchronousIO",
+
+	"Cluster.ElectionTimeoutInMs",
+	"Cluster.WorkerSamplePeriodInMs",
+	"Cluster.SupervisorSamplePeriodInMs",
+	"Cluster.StabilizationTimeInSec",
+	"Cluster.TimeBeforeAddingReplicaInSec",
+	"Cluster.TimeBeforeMovingToRehabInSec",
+	"Cluster.TimeBeforeRotatingPreferredNodeInSec",
+	"Cluster.MoveToRehabGraceTimeInSec",
+	"Cluster.AddReplicaTimeoutInSec",
+	"Cluster.RotatePreferredNodeGraceTimeInSec",
+	"Cluster.OperationTimeoutInSec",
+	"Cluster.StatsStabilizationTimeInSec",
+	"Cluster.TcpConnectionTimeoutInMs",
+	"Cluster.TcpReceiveBufferSizeInBytes",
+	"Cluster.TcpSendBufferSizeInBytes",
+	"Cluster.CompareExchangeExpiredDeleteFrequencyInSec",
+	"Cluster.CompareExchangeTombstonesCleanupIntervalInMin",
+	"Cluster.LogHistoryMaxEntries",
+	"Cluster.MaxChangeVectorDistance",
+	"Cluster.MaximalAllowedClusterVersion",
+	"Cluster.DisableAtomicDocumentWrites",
+	"Cluster.HardDeleteOnReplacement",
+
+	"Databases.ConcurrentLoadTimeoutInSec",
+	"Databases.MaxConcurrentLoads",
+	"Databases.MaxIdleTimeInSec",
+	"Databases.FrequencyToCheckForIdleInSec",
+	"Databases.OperationTimeoutInSec",
+	"Databases.CollectionOperationTimeoutInSec",
+	"Databases.QueryTimeoutInSec",
+	"Databases.QueryOperationTimeoutInSec",
+	"Databases.PulseReadTransactionLimitInMb",
+	"Databases.DeepCleanupThresholdInMin",
+	"Databases.RegularCleanupThresholdInMin",
+	"Databases.CompressRevisionsDefault",
+	"Databases.CompressAllCollectionsDefault",
+
+	"Indexing.RunInMemory",
+	"Indexing.Disable",
+	"Indexing.TempPath",
+	"Indexing.MapTimeoutInSec",
+	"Indexing.MapTimeoutAfterEtagReachedInMin",
+	"Indexing.MapBatchSize",
+	"Indexing.MaxStepsForScript",
+	"Indexing.MaxTimeForDocumentTransactionToRemainOpenInSec",
+	"Indexing.MaxTimeToWaitAfterFlushAndSyncWhenReplacingSideBySideIndexInSec",
+	"Indexing.MinNumberOfMapAttemptsAfterWhichBatchWillBeCanceledIfRunningLowOnMemory",
+	"Indexing.NumberOfConcurrentStoppedBatchesIfRunningLowOnMemory",
+	"Indexing.TimeToWaitBeforeDeletingAutoIndexMarkedAsIdleInHrs",
+	"Indexing.TimeToWaitBeforeMarkingAutoIndexAsIdleInMin",
+	"Indexing.DisableQueryOptimizerGeneratedIndexes",
+	"Indexing.Static.SearchEngineType",
+	"Indexing.Auto.SearchEngineType",
+	"Indexing.Analyzers.Default",
+	"Indexing.Analyzers.Exact.Default",
+	"Indexing.Analyzers.Search.Default",
+	"Indexing.ManagedAllocationsBatchSizeLimitInMb",
+	"Indexing.MaxNumberOfConcurrentlyRunningIndexes",
+	"Indexing.History.NumberOfRevisions",
+	"Indexing.QueryClauseCache.Disabled",
+	"Indexing.ErrorIndexStartupBehavior",
+	"Indexing.ScratchSpaceLimitInMb",
+	"Indexing.GlobalScratchSpaceLimitInMb",
+	"Indexing.CleanupIntervalInMin",
+	"Indexing.Throttling.TimeIntervalInMs",
+	"Indexing.Metrics.Enabled",
+	"Indexing.TransactionSizeLimitInMb",
+	"Indexing.EncryptedTransactionSizeLimitInMb",
+	"Indexing.LargeSegmentSizeToMergeInMb",
+	"Indexing.MaximumSizePerSegmentInMb",
+	"Indexing.MergeFactor",
+	"Indexing.NumberOfLargeSegmentsToMergeInSingleBatch",
+	"Indexing.MaxTimeForMergesInSec",
+
+	"Storage.TempPath",
+	"Storage.TransactionsModeDuration",
+	"Storage.MaxConcurrentFlushes",
+	"Storage.NumberOfConcurrentSyncsPerPhysicalDrive",
+	"Storage.CompressTxAboveSizeInBytes",
+	"Storage.ForceUsing32BitsPager",
+	"Storage.EnablePrefetching",
+	"Storage.MaxScratchBufferSizeInMb",
+	"Storage.OnDirectoryInitialize.Exec",
+	"Storage.OnDirectoryInitialize.Exec.Arguments",
+	"Storage.OnDirectoryInitialize.Exec.TimeoutInSec",
+	"Storage.SyncJournalsCountThreshold",
+	"Storage.TimeToSyncAfterFlushInSec",
+	"Storage.IgnoreInvalidJournalErrors",
+	"Storage.SkipChecksumValidationOnDatabaseLoading",
+
+	"Memory.LowMemoryLimitInMb",
+	"Memory.LowMemoryCommitLimitInMb",
+	"Memory.MinimumFreeCommittedMemoryPercentage",
+	"Memory.MaxFreeCommittedMemoryToKeepInMb",
+	"Memory.UseTotalDirtyMemInsteadOfMemUsage",
+	"Memory.EnableHighTemporaryDirtyMemoryUse",
+	"Memory.TemporaryDirtyMemoryAllowedPercentage",
+	"Memory.TemporaryDirtyMemoryChecksPeriodInSec",
+
+	"Monitoring.Snmp.Enabled",
+	"Monitoring.Snmp.Port",
+	"Monitoring.Snmp.Community",
+	"Monitoring.Snmp.SupportedVersions",
+	"Monitoring.Snmp.AuthenticationProtocol",
+	"Monitoring.Snmp.AuthenticationUser",
+	"Monitoring.Snmp.AuthenticationPassword",
+	"Monitoring.Snmp.PrivacyProtocol",
+	"Monitoring.Snmp.PrivacyPassword",
+	"Monitoring.Snmp.AuthenticationProtocol.Secondary",
+	"Monitoring.Snmp.AuthenticationUser.Secondary",
+	"Monitoring.Snmp.AuthenticationPassword.Secondary",
+	"Monitoring.Snmp.PrivacyProtocol.Secondary",
+	"Monitoring.Snmp.PrivacyPassword.Secondary",
+	"Monitoring.Snmp.DisableTimeWindowChecks",
+	"Monitoring.Cpu.Exec",
+	"Monitoring.Cpu.Exec.Arguments",
+
+	"Queries.MaxClauseCount",
+	"Queries.RegexTimeoutInMs",
+	"Patching.MaxStepsForScript",
+	"Patching.AllowStringCompilation",
+	"Patching.MaxNumberOfCachedScripts",
+	"Patching.StrictMode",
+
+	"Replication.ActiveConnectionTimeoutInSec",
+	"Replication.RetryReplicateAfterInSec",
+	"Replication.RetryMaxTimeoutInSec",
+	"Replication.MaxItemsCount",
+	"Replication.MaxSizeToSendInMb",
+	"Replication.ReplicationMinimalHeartbeatInSec",
+
+	"Subscriptions.ConcurrentConnections",
+	"Subscriptions.MaxNumberOfConcurrentConnections",
+
+	"Backup.TempPath",
+	"Backup.LocalRootPath",
+	"Backup.AllowedDestinations",
+	"Backup.AllowedAwsRegions",
+	"Backup.MaxNumberOfConcurrentBackups",
+	"Backup.ConcurrentBackupsDelayInSec",
+	"Backup.LowMemoryBackupDelayInMin",
+	"Backup.ZipCompressionLevel",
+	"Backup.CompressionAlgorithm",
+	"Backup.Snapshot.CompressionLevel",
+	"Backup.Snapshot.CompressionAlgorithm",
+	"Backup.CloudStorageOperationTimeoutInMin",
+
+	"Tombstones.CleanupIntervalInMin",
+	"Tombstones.RetentionTimeWithReplicationHubInHrs",
+	"Tombstones.CleanupIntervalWithReplicationHubInMin",
+
+	"TransactionMerger.MaxTimeToWaitForPreviousTxInMs",
+	"TransactionMerger.MaxTxSizeInMb",
+	"TransactionMerger.MaxTimeToWaitForPreviousTxBeforeRejectingInMs",
+
+	"PerformanceHints.Documents.HugeDocumentsCollectionSize",
+	"PerformanceHints.Documents.HugeDocumentSizeInMb",
+	"PerformanceHints.Indexing.AlertWhenSourceDocumentIncludedInOutput",
+	"PerformanceHints.Indexing.MaxIndexOutputsPerDocument",
+	"PerformanceHints.Indexing.MaxDepthOfRecursionInLinqSelect",
+	"PerformanceHints.MaxNumberOfResults",
+	"PerformanceHints.TooLongRequestThresholdInSec",
+	"PerformanceHints.Memory.MinSwapSizeInMb",
+
+	"ETL.ExtractAndTransformTimeoutInSec",
+	"ETL.MaxNumberOfExtractedDocuments",
+	"ETL.MaxNumberOfExtractedItems",
+	"ETL.MaxBatchSizeInMb",
+	"ETL.MaxFallbackTimeInSec",
+	"ETL.SQL.CommandTimeoutInSec",
+
+	"Server.ProcessAffinityMask",
+	"Server.IndexingAffinityMask",
+	"Server.NumberOfUnusedCoresByIndexes",
+	"Server.MaxTimeForTaskToWaitForDatabaseToLoadInSec",
+	"Server.CpuCredits.Base",
+	"Server.CpuCredits.Max",
+	"Server.CpuCredits.ExhaustionBackgroundTasksThreshold",
+	"Server.CpuCredits.ExhaustionFailoverThreshold",
+	"Server.CpuCredits.Exec",
+	"Server.CpuCredits.Exec.Arguments",
+	"Server.CpuCredits.Exec.SyncIntervalInSec",
+	"Server.CpuCredits.Exec.TimeoutInSec",
+
+	"TrafficWatch.Mode",
+	"TrafficWatch.Databases",
+	"TrafficWatch.StatusCodes",
+	"TrafficWatch.MinimumResponseSizeInBytes",
+	"TrafficWatch.MinimumRequestSizeInBytes",
+	"TrafficWatch.MinimumDurationInMs",
+	"TrafficWatch.HttpMethods",
+	"TrafficWatch.ChangeTypes",
+	"TrafficWatch.CertificateThumbprints",
+
+	"Integrations.PostgreSQL.Enabled",
+	"Integrations.PostgreSQL.Port",
+}
+
+// MAX_SETTING_TYPOS is the edit distance up to which an unknown setting is taken for a typo of a known one.
+const MAX_SETTING_TYPOS int = 2
+
+// similarSetting returns the known setting key is a near miss of, or "" when key is known or not close to any.
+func similarSetting(key string) string {
+	lower := strings.ToLower(key)
+	suggestion := ""
+	closest := MAX_SETTING_TYPOS + 1
+	for _, known := range knownSettings {
+		distance := editDistance(lower, strings.ToLower(known))
+		if distance == 0 {
+			return ""
+		}
+		if distance < closest {
+			suggestion, closest = known, distance
+		}
+	}
+	return suggestion
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min3(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func min3(a int, b int, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
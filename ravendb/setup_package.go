@@ -0,0 +1,386 @@
+package ravendb
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	SETUP_PACKAGE_GENERATE_LOCAL      string = "local"
+	SETUP_PACKAGE_GENERATE_FIRST_HOST string = "first_host"
+	SETUP_PACKAGE_GENERATE_SERVER     string = "server"
+)
+
+// setupWizardUrl is where a freshly installed server, still in setup mode, listens.
+const setupWizardUrl = "http://127.0.0.1:53700"
+
+// SetupPackage is the package created by the RavenDB setup wizard, or the SetupInfo to create it from with
+// rvn create-setup-package.
+type SetupPackage struct {
+	Archive    []byte
+	SetupInfo  []byte
+	Url        string
+	Mode       string
+	GenerateOn string
+	RvnPath    string
+}
+
+func setupPackageSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "The setup package (ZIP) of the RavenDB setup wizard, used instead of certificate. Exactly one of path, content, url and setup_info must be set.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"path": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The local path of the package.",
+				},
+				"content": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Sensitive:    true,
+					Description:  "The base64 encoded package.",
+					ValidateFunc: validation.StringIsBase64,
+				},
+				"url": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "An https:// or s3://bucket/key url to download the package from. S3 is accessed with the default AWS credentials chain.",
+				},
+				"setup_info": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Sensitive:    true,
+					Description:  "A SetupInfo JSON document the package is created from with rvn create-setup-package.",
+					ValidateFunc: validation.StringIsJSON,
+				},
+				"mode": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "own-certificate",
+					Description:  "The setup mode setup_info is for - own-certificate or lets-encrypt.",
+					ValidateFunc: validation.StringInSlice([]string{"own-certificate", "lets-encrypt"}, false),
+				},
+				"generate_on": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      SETUP_PACKAGE_GENERATE_LOCAL,
+					Description:  "Where the package is created - local or first_host with rvn, or server by the secured setup of the first node, which must still be in setup mode. RavenDB must already be installed on the first host unless local.",
+					ValidateFunc: validation.StringInSlice([]string{SETUP_PACKAGE_GENERATE_LOCAL, SETUP_PACKAGE_GENERATE_FIRST_HOST, SETUP_PACKAGE_GENERATE_SERVER}, false),
+				},
+				"rvn_path": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The path of rvn. Defaults to rvn locally and to the one installed with RavenDB on the first host.",
+				},
+			},
+		},
+	}
+}
+
+// setupPackageArchiveSchema holds the package created from setup_info or downloaded from url, so it is created or downloaded
+// once and not on every read.
+func setupPackageArchiveSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Sensitive:   true,
+		Description: "The setup package created from setup_package.setup_info or downloaded from setup_package.url, base64 encoded.",
+	}
+}
+
+func setupPackageHashSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The SHA-256 of the setup package the cluster was last applied with, hex encoded.",
+	}
+}
+
+func parseSetupPackage(d *schema.ResourceData) (*SetupPackage, error) {
+	list := d.Get("setup_package").(*schema.Set).List()
+	if len(list) == 0 {
+		return nil, nil
+	}
+	value := list[0].(map[string]interface{})
+
+	var sources []string
+	for _, key := range []string{"path", "content", "url", "setup_info"} {
+		if value[key].(string) != "" {
+			sources = append(sources, key)
+		}
+	}
+	if len(sources) != 1 {
+		return nil, errors.New("setup_package requires exactly one of path, content, url or setup_info")
+	}
+	if value["generate_on"].(string) == SETUP_PACKAGE_GENERATE_SERVER && value["mode"].(string) != "own-certificate" {
+		return nil, errors.New("setup_package can only be created by the server in own-certificate mode")
+	}
+
+	setupPackage := &SetupPackage{
+		Mode:       value["mode"].(string),
+		GenerateOn: value["generate_on"].(string),
+		RvnPath:    value["rvn_path"].(string),
+	}
+	var err error
+	switch sources[0] {
+	case "path":
+		setupPackage.Archive, err = ioutil.ReadFile(value["path"].(string))
+	case "content":
+		setupPackage.Archive, err = base64.StdEncoding.DecodeString(value["content"].(string))
+	case "url":
+		setupPackage.Url = value["url"].(string)
+		if !d.HasChange("setup_package") {
+			setupPackage.Archive, err = storedSetupPackage(d)
+		}
+	case "setup_info":
+		setupPackage.SetupInfo = []byte(value["setup_info"].(string))
+		if !d.HasChange("setup_package") {
+			setupPackage.Archive, err = storedSetupPackage(d)
+		}
+	}
+	return setupPackage, err
+}
+
+// resolveSetupPackage takes the cluster certificate from the setup package, as well as the license when none is given. A package
+// given as SetupInfo or url has no archive until fetchSetupPackage created or downloaded it.
+func (sc *ServerConfig) resolveSetupPackage() error {
+	if sc.SetupPackage == nil {
+		return nil
+	}
+	if sc.ClusterCertificate != nil {
+		return errors.New("certificate and setup_package are mutually exclusive")
+	}
+	if sc.SetupPackage.Archive == nil {
+		return nil
+	}
+
+	var err error
+	sc.ClusterCertificate, err = clusterCertificateFromPackage(sc.SetupPackage.Archive)
+	if err != nil || sc.License != nil {
+		return err
+	}
+	sc.License, err = licenseFromPackage(sc.SetupPackage.Archive)
+	return err
+}
+
+// fetchSetupPackage creates the setup package of setup_info, with rvn or by the server, or downloads the one of url, unless a
+// previous apply already did, and keeps it in setup_package_archive for the later reads. It runs on create and update only, as
+// it may have to reach the first host, whose setup wizard is gone once the server is set up, and so an unreachable url doesn't
+// fail every plan. The hash of the package applied is kept in setup_package_sha256.
+func (sc *ServerConfig) fetchSetupPackage(d *schema.ResourceData) error {
+	if sc.SetupPackage == nil {
+		err := d.Set("setup_package_archive", "")
+		if err != nil {
+			return err
+		}
+		return d.Set("setup_package_sha256", "")
+	}
+	if sc.SetupPackage.SetupInfo == nil && sc.SetupPackage.Url == "" {
+		err := d.Set("setup_package_archive", "")
+		if err != nil {
+			return err
+		}
+		return d.Set("setup_package_sha256", setupPackageHash(sc.SetupPackage.Archive))
+	}
+	if sc.SetupPackage.Archive != nil || sc.ClusterCertificate != nil {
+		return nil
+	}
+
+	var err error
+	switch {
+	case sc.SetupPackage.Url != "":
+		sc.SetupPackage.Archive, err = downloadSetupPackage(sc.SetupPackage.Url)
+	case sc.SetupPackage.GenerateOn == SETUP_PACKAGE_GENERATE_FIRST_HOST, sc.SetupPackage.GenerateOn == SETUP_PACKAGE_GENERATE_SERVER:
+		sc.SetupPackage.Archive, err = sc.generateSetupPackageOnHost(sc.Hosts[0])
+	default:
+		sc.SetupPackage.Archive, err = sc.SetupPackage.generateLocally()
+	}
+	if err != nil {
+		return fmt.Errorf("fetching the setup package: %w", err)
+	}
+	err = d.Set("setup_package_archive", base64.StdEncoding.EncodeToString(sc.SetupPackage.Archive))
+	if err != nil {
+		return err
+	}
+	err = d.Set("setup_package_sha256", setupPackageHash(sc.SetupPackage.Archive))
+	if err != nil {
+		return err
+	}
+	return sc.resolveSetupPackage()
+}
+
+// storedSetupPackage returns the package created or downloaded by a previous apply, or nil when there is none.
+func storedSetupPackage(d *schema.ResourceData) ([]byte, error) {
+	archive, err := base64.StdEncoding.DecodeString(d.Get("setup_package_archive").(string))
+	if err != nil || len(archive) == 0 {
+		return nil, err
+	}
+	return archive, nil
+}
+
+func setupPackageHash(archive []byte) string {
+	if archive == nil {
+		return ""
+	}
+	sum := sha256.Sum256(archive)
+	return hex.EncodeToString(sum[:])
+}
+
+func (sp *SetupPackage) rvnArguments(setupInfoPath string, packagePath string) []string {
+	return []string{"create-setup-package", "--mode=" + sp.Mode, "--setup-json-path=" + setupInfoPath, "--package-out-path=" + packagePath}
+}
+
+func (sp *SetupPackage) generateLocally() ([]byte, error) {
+	dir, err := ioutil.TempDir("", "ravendb-setup")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	setupInfoPath := filepath.Join(dir, "setup.json")
+	err = ioutil.WriteFile(setupInfoPath, sp.SetupInfo, 0600)
+	if err != nil {
+		return nil, err
+	}
+	rvn := sp.RvnPath
+	if rvn == "" {
+		rvn = "rvn"
+	}
+	packagePath := filepath.Join(dir, "package.zip")
+	output, err := exec.Command(rvn, sp.rvnArguments(setupInfoPath, packagePath)...).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("%w with output:\n%s", err, output)
+	}
+	return ioutil.ReadFile(packagePath)
+}
+
+func (sc *ServerConfig) generateSetupPackageOnHost(publicIP string) ([]byte, error) {
+	const setupInfoPath = "/tmp/ravendb-setup.json"
+	const packagePath = "/tmp/ravendb-setup.zip"
+	rvn := sc.SetupPackage.RvnPath
+	if rvn == "" {
+		rvn = "/usr/lib/ravendb/server/rvn"
+	}
+	cmd := "sudo " + rvn + " " + strings.Join(sc.SetupPackage.rvnArguments(setupInfoPath, packagePath), " ")
+	if sc.SetupPackage.GenerateOn == SETUP_PACKAGE_GENERATE_SERVER {
+		// the server creates the packages of all the nodes itself, as its setup wizard does
+		cmd = "sudo curl -sSf -X POST -H 'Content-Type: application/json' --data-binary @" + setupInfoPath + " -o " + packagePath + " " + setupWizardUrl + "/setup/secured"
+	}
+
+	var archive []byte
+	err := sc.onHost(publicIP, func(conn Transport, stdoutBuf *nodeLog) error {
+		defer runCommand(conn, "sudo rm -f "+setupInfoPath+" "+packagePath)
+		err := upload(conn, stdoutBuf, setupInfoPath, sc.SetupPackage.SetupInfo)
+		if err != nil {
+			return err
+		}
+		err = sc.execute(publicIP, []string{cmd}, "", stdoutBuf, conn)
+		if err != nil {
+			return err
+		}
+		archive, err = readFileContents(packagePath, stdoutBuf, conn)
+		return err
+	})
+	return archive, err
+}
+
+func downloadSetupPackage(packageUrl string) ([]byte, error) {
+	u, err := url.Parse(packageUrl)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "https":
+		response, err := http.Get(packageUrl)
+		if err != nil {
+			return nil, err
+		}
+		defer response.Body.Close()
+		if response.StatusCode != http.StatusOK {
+			return nil, errors.New("downloading the setup package from " + packageUrl + " failed with " + response.Status)
+		}
+		return ioutil.ReadAll(response.Body)
+	case "s3":
+		sess, err := session.NewSessionWithOptions(session.Options{
+			SharedConfigState: session.SharedConfigEnable,
+		})
+		if err != nil {
+			return nil, err
+		}
+		object, err := s3.New(sess).GetObject(&s3.GetObjectInput{
+			Bucket: aws.String(u.Host),
+			Key:    aws.String(strings.TrimPrefix(u.Path, "/")),
+		})
+		if err != nil {
+			return nil, err
+		}
+		defer object.Body.Close()
+		return ioutil.ReadAll(object.Body)
+	}
+	return nil, errors.New("unsupported setup package url " + packageUrl + ", expected https:// or s3://")
+}
+
+// clusterCertificateFromPackage returns the cluster certificate (pfx) in a setup package. Every node folder
+// holds the same one, so the first found is used.
+func clusterCertificateFromPackage(archive []byte) ([]byte, error) {
+	certificate, err := readFromPackage(archive, func(name string) bool {
+		return strings.HasPrefix(name, "cluster.server.certificate.") && strings.HasSuffix(name, ".pfx")
+	})
+	if err == nil && certificate == nil {
+		return nil, errors.New("the setup package does not contain a cluster certificate")
+	}
+	return certificate, err
+}
+
+func licenseFromPackage(archive []byte) ([]byte, error) {
+	license, err := readFromPackage(archive, func(name string) bool {
+		return name == "license.json"
+	})
+	if err == nil && license == nil {
+		return nil, errors.New("license is required, the setup package does not contain one")
+	}
+	return license, err
+}
+
+// readFromPackage returns the contents of the first file of the setup package whose name matches, or nil when
+// there is none.
+func readFromPackage(archive []byte, matches func(name string) bool) ([]byte, error) {
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range reader.File {
+		if !matches(path.Base(file.Name)) {
+			continue
+		}
+		content, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer content.Close()
+		return ioutil.ReadAll(content)
+	}
+	return nil, nil
+}
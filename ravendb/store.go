@@ -0,0 +1,393 @@
+package ravendb
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/ravendb/ravendb-go-client"
+	"github.com/ravendb/terraform-provider-ravendb/operations"
+	"github.com/ravendb/terraform-provider-ravendb/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// connectionSchema holds the attributes every resource that talks to an existing cluster uses to reach it.
+func connectionSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"urls": {
+			Type:        schema.TypeList,
+			Required:    true,
+			Description: "The urls of the RavenDB cluster nodes.",
+			MinItems:    1,
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+			},
+		},
+		"certificate": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			Sensitive:     true,
+			Description:   "The client certificate (pfx) used to authenticate against a secured cluster.",
+			ValidateFunc:  validation.StringIsBase64,
+			ConflictsWith: []string{"certificate_pem"},
+		},
+		"certificate_password": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+			Description: "The password the pfx of certificate is protected with.",
+		},
+		"certificate_pem": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  "The client certificate in PEM, as an alternative to a pfx certificate.",
+			RequiredWith: []string{"private_key_pem"},
+		},
+		"private_key_pem": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Sensitive:    true,
+			Description:  "The PEM private key of certificate_pem.",
+			RequiredWith: []string{"certificate_pem"},
+		},
+		"tls": tlsSchema(),
+	}
+}
+
+// TLSOptions change how the certificate of the server is verified by the stores authenticating with a client
+// certificate.
+type TLSOptions struct {
+	CaBundle           []byte
+	ServerName         string
+	InsecureSkipVerify bool
+}
+
+func tlsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "How the certificate of the server is verified on the management connection.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"ca_bundle": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "PEM encoded certificates of the authorities the server certificate may be issued by, on top of the system ones.",
+				},
+				"server_name": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The name sent as SNI and expected in the server certificate, instead of the hostname of the url.",
+				},
+				"insecure_skip_verify": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Description: "Doesn't verify the server certificate at all. For lab use only.",
+				},
+			},
+		},
+	}
+}
+
+func parseTLSOptions(d *schema.ResourceData) (*TLSOptions, error) {
+	for _, v := range d.Get("tls").(*schema.Set).List() {
+		value := v.(map[string]interface{})
+		options := &TLSOptions{
+			ServerName:         value["server_name"].(string),
+			InsecureSkipVerify: value["insecure_skip_verify"].(bool),
+		}
+		if bundle := value["ca_bundle"].(string); bundle != "" {
+			options.CaBundle = []byte(bundle)
+			if x509.NewCertPool().AppendCertsFromPEM(options.CaBundle) == false {
+				return nil, errors.New("tls.ca_bundle does not contain any PEM certificate")
+			}
+		}
+		return options, nil
+	}
+	return nil, nil
+}
+
+// storeTLS is the TLS configuration of a store: its options and the certificate of the server it trusts.
+type storeTLS struct {
+	options    *TLSOptions
+	trustStore *x509.Certificate
+}
+
+// storeTLSConfigs holds the TLS configuration of every store with TLSOptions. The client only offers the process
+// wide ravendb.HTTPClientPostProcessor to adjust the http clients of a store, and a client only carries the client
+// certificate of its store. Every such store is given its own copy of the certificate, so its clients are told
+// apart by the address of the copy rather than by its content.
+var storeTLSConfigs = struct {
+	sync.Mutex
+	configs map[*byte]storeTLS
+}{configs: map[*byte]storeTLS{}}
+
+func init() {
+	ravendb.HTTPClientPostProcessor = applyTLSOptions
+}
+
+// withTLSOptions returns the copy of certificate a new store uses, registered with the options of the store.
+func withTLSOptions(certificate *tls.Certificate, trustStore *x509.Certificate, options *TLSOptions) *tls.Certificate {
+	own := *certificate
+	own.Certificate = make([][]byte, len(certificate.Certificate))
+	for i, der := range certificate.Certificate {
+		own.Certificate[i] = append([]byte(nil), der...)
+	}
+	storeTLSConfigs.Lock()
+	defer storeTLSConfigs.Unlock()
+	storeTLSConfigs.configs[&own.Certificate[0][0]] = storeTLS{options: options, trustStore: trustStore}
+	return &own
+}
+
+// applyTLSOptions adjusts the TLS configuration of the http clients created by a store with TLSOptions.
+func applyTLSOptions(client *http.Client) {
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok || transport.TLSClientConfig == nil || len(transport.TLSClientConfig.Certificates) == 0 {
+		return
+	}
+	config := transport.TLSClientConfig
+	leaf := config.Certificates[0].Certificate
+	if len(leaf) == 0 || len(leaf[0]) == 0 {
+		return
+	}
+	storeTLSConfigs.Lock()
+	store, ok := storeTLSConfigs.configs[&leaf[0][0]]
+	storeTLSConfigs.Unlock()
+	if !ok {
+		return
+	}
+
+	options := store.options
+	if len(options.CaBundle) > 0 {
+		// a pool of its own, the system pool and the trusted server certificate aren't changed for other clients
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if store.trustStore != nil {
+			pool.AddCert(store.trustStore)
+		}
+		pool.AppendCertsFromPEM(options.CaBundle)
+		config.RootCAs = pool
+	}
+	if options.ServerName != "" {
+		config.ServerName = options.ServerName
+	}
+	config.InsecureSkipVerify = options.InsecureSkipVerify
+}
+
+// withConnectionSchema adds the connection attributes to a resource schema.
+func withConnectionSchema(s map[string]*schema.Schema) map[string]*schema.Schema {
+	for key, value := range connectionSchema() {
+		s[key] = value
+	}
+	return s
+}
+
+// getStoreFromData returns the store of the cluster described by the connection attributes of d. Stores
+// are shared through the provider meta and closed when the provider stops.
+func getStoreFromData(d *schema.ResourceData, meta interface{}, database string) (*ravendb.DocumentStore, error) {
+	list := d.Get("urls").([]interface{})
+	urls := make([]string, len(list))
+	for i, u := range list {
+		urls[i] = u.(string)
+	}
+
+	certificate, err := clientCertificateFromData(d)
+	if err != nil {
+		return nil, err
+	}
+	if certificate == nil {
+		for _, u := range urls {
+			if strings.HasPrefix(strings.ToLower(u), "https://") {
+				return nil, errors.New("certificate or certificate_pem is required to connect to " + u)
+			}
+		}
+	}
+	options, err := parseTLSOptions(d)
+	if err != nil {
+		return nil, err
+	}
+	return meta.(*storeCache).get(storeKey(urls, database, certificate, options), func() (*ravendb.DocumentStore, error) {
+		return newStore(urls, database, certificate, options)
+	})
+}
+
+// storeKey identifies the store newStore creates for the given arguments in a storeCache. The certificate and the
+// options are part of it, so a store isn't reused once they change.
+func storeKey(urls []string, database string, certificate *tls.Certificate, options *TLSOptions) string {
+	key := strings.Join(urls, ",") + "|" + database
+	if certificate != nil {
+		key += fmt.Sprintf("|%x", sha256.Sum256(certificate.Certificate[0]))
+	}
+	if options != nil {
+		key += fmt.Sprintf("|%x|%s|%t", sha256.Sum256(options.CaBundle), options.ServerName, options.InsecureSkipVerify)
+	}
+	return key
+}
+
+// clientCertificateFromData returns the client certificate of the connection attributes, given either as PEM or
+// as a pfx, or nil when there is none.
+func clientCertificateFromData(d *schema.ResourceData) (*tls.Certificate, error) {
+	if certificatePem := d.Get("certificate_pem").(string); certificatePem != "" {
+		certificate, err := tls.X509KeyPair([]byte(certificatePem), []byte(d.Get("private_key_pem").(string)))
+		if err != nil {
+			return nil, err
+		}
+		return &certificate, nil
+	}
+
+	pfx, err := base64.StdEncoding.DecodeString(d.Get("certificate").(string))
+	if err != nil {
+		return nil, err
+	}
+	if allZero(pfx) {
+		return nil, nil
+	}
+	return pfxCertificate(pfx, d.Get("certificate_password").(string))
+}
+
+// pfxCertificate converts pfx, protected by password unless it is empty, to a client certificate.
+func pfxCertificate(pfx []byte, password string) (*tls.Certificate, error) {
+	var key, crt []byte
+	var err error
+	if password == "" {
+		key, crt, err = utils.PfxToPem(pfx)
+	} else {
+		key, crt, err = utils.PfxToPemWithPassword(pfx, password)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	certificate, err := tls.X509KeyPair(crt, key)
+	if err != nil {
+		return nil, err
+	}
+	return &certificate, nil
+}
+
+// newStore initializes a document store for the given urls. The connection is unsecured when no certificate is given.
+func newStore(urls []string, database string, certificate *tls.Certificate, options *TLSOptions) (*ravendb.DocumentStore, error) {
+	store := ravendb.NewDocumentStore(urls, database)
+
+	if certificate != nil {
+		x509cert, err := x509.ParseCertificate(certificate.Certificate[0])
+		if err != nil {
+			return nil, err
+		}
+		if options != nil {
+			certificate = withTLSOptions(certificate, x509cert, options)
+		}
+		store.TrustStore = x509cert
+		store.Certificate = certificate
+	}
+
+	if err := store.Initialize(); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// waitForOperation polls the state of a long running database operation until it completes or the timeout expires.
+func waitForOperation(store *ravendb.DocumentStore, database string, id int64, timeout time.Duration) (operations.OperationState, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		state := operations.OperationGetOperationState{
+			Database: database,
+			Id:       id,
+		}
+		err := executeWithRetries(store, &state)
+		if err != nil {
+			return state.Result, err
+		}
+		switch state.Result.Status {
+		case "Completed":
+			return state.Result, nil
+		case "Faulted", "Canceled":
+			return state.Result, errors.New("operation " + strconv.FormatInt(id, 10) + " " + strings.ToLower(state.Result.Status) + ": " + string(state.Result.Result))
+		}
+		if time.Now().After(deadline) {
+			return state.Result, errors.New("timed out waiting for operation " + strconv.FormatInt(id, 10) + " to complete")
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// storeCache keeps initialized document stores by key so they are reused instead of being opened
+// again for every request, along with the last cluster topology read through each of them. The
+// provider meta holds one for the lifetime of the provider, shared by every resource, so the
+// deploy and read phases share a store per node.
+type storeCache struct {
+	mu         sync.Mutex
+	stores     map[string]*cachedStore
+	topologies clusterTopologies
+}
+
+// cachedStore is a store being initialized, ready once initialized is closed.
+type cachedStore struct {
+	initialized chan struct{}
+	store       *ravendb.DocumentStore
+	err         error
+}
+
+func newStoreCache() *storeCache {
+	return &storeCache{
+		stores:     make(map[string]*cachedStore),
+		topologies: make(clusterTopologies),
+	}
+}
+
+// get returns the store of key, created by create the first time. The store is initialized outside of the lock,
+// so stores of other keys aren't held up by it, and concurrent callers of the same key wait for it. A store that
+// failed to initialize isn't kept, the next call creates it again.
+func (c *storeCache) get(key string, create func() (*ravendb.DocumentStore, error)) (*ravendb.DocumentStore, error) {
+	c.mu.Lock()
+	cached, ok := c.stores[key]
+	if !ok {
+		cached = &cachedStore{initialized: make(chan struct{})}
+		c.stores[key] = cached
+	}
+	c.mu.Unlock()
+
+	if ok {
+		<-cached.initialized
+		return cached.store, cached.err
+	}
+	cached.store, cached.err = create()
+	if cached.err != nil {
+		c.mu.Lock()
+		delete(c.stores, key)
+		c.mu.Unlock()
+	}
+	close(cached.initialized)
+	return cached.store, cached.err
+}
+
+func (c *storeCache) close() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	stores := c.stores
+	c.stores = make(map[string]*cachedStore)
+	c.topologies = make(clusterTopologies)
+	c.mu.Unlock()
+	for _, cached := range stores {
+		<-cached.initialized
+		if cached.store != nil {
+			cached.store.Close()
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package ravendb
+
+import (
+	"crypto/tls"
+	"errors"
+	"github.com/ravendb/ravendb-go-client"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestStoreCacheCreatesStoreOnce(t *testing.T) {
+	cache := newStoreCache()
+	store := ravendb.NewDocumentStore([]string{"http://127.0.0.1:8080"}, "")
+	var created int32
+	create := func() (*ravendb.DocumentStore, error) {
+		atomic.AddInt32(&created, 1)
+		return store, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			actual, err := cache.get("a", create)
+			if err != nil || actual != store {
+				t.Errorf("expected the cached store, got %v, %v", actual, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if created != 1 {
+		t.Errorf("expected the store to be created once, it was created %d times", created)
+	}
+
+	_, err := cache.get("b", func() (*ravendb.DocumentStore, error) {
+		return nil, errors.New("unreachable")
+	})
+	if err == nil {
+		t.Fatal("expected the error of create")
+	}
+	actual, err := cache.get("b", create)
+	if err != nil || actual != store {
+		t.Errorf("expected a failed store to be created again, got %v, %v", actual, err)
+	}
+}
+
+func TestTLSOptionsArePerStore(t *testing.T) {
+	certificate := &tls.Certificate{Certificate: [][]byte{[]byte("the same client certificate")}}
+	first := withTLSOptions(certificate, nil, &TLSOptions{ServerName: "a.example.com"})
+	second := withTLSOptions(certificate, nil, &TLSOptions{ServerName: "b.example.com", InsecureSkipVerify: true})
+
+	for expected, own := range map[string]*tls.Certificate{"a.example.com": first, "b.example.com": second} {
+		config := &tls.Config{Certificates: []tls.Certificate{*own}}
+		applyTLSOptions(&http.Client{Transport: &http.Transport{TLSClientConfig: config}})
+		if config.ServerName != expected || config.InsecureSkipVerify != (expected == "b.example.com") {
+			t.Errorf("expected the options of the store of %s, got %s, %t", expected, config.ServerName, config.InsecureSkipVerify)
+		}
+	}
+
+	config := &tls.Config{Certificates: []tls.Certificate{*certificate}}
+	applyTLSOptions(&http.Client{Transport: &http.Transport{TLSClientConfig: config}})
+	if config.ServerName != "" || config.InsecureSkipVerify {
+		t.Error("expected a store without options to be left as it is")
+	}
+}
@@ -0,0 +1,55 @@
+package ravendb
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+)
+
+// Transport runs commands and writes files on a host. The deploy reaches its hosts only through a transport, so
+// other ways to reach them than SSH can be added without touching it.
+type Transport interface {
+	// Run runs cmd on the host and returns once it exited, with a non nil error when it failed.
+	Run(cmd string, stdout io.Writer, stderr io.Writer) error
+	// Upload writes the size bytes of content to path on the host, created with mode, e.g. 0660, when it doesn't exist.
+	Upload(path string, content io.Reader, size int64, mode string) error
+	Close() error
+}
+
+// dialer opens a transport to publicIP, giving up after timeout. A zero timeout waits as long as the transport
+// allows.
+type dialer func(publicIP string, timeout time.Duration) (Transport, error)
+
+// dialHost opens a transport to publicIP with sc.dial, or over SSH when it isn't set.
+func (sc *ServerConfig) dialHost(publicIP string, timeout time.Duration) (Transport, error) {
+	if sc.dial != nil {
+		return sc.dial(publicIP, timeout)
+	}
+	return sc.SSH.dial(publicIP, timeout)
+}
+
+// runCommand runs cmd on the host of conn and returns its standard output and error interleaved.
+func runCommand(conn Transport, cmd string) ([]byte, error) {
+	var output lockedBuffer
+	err := conn.Run(cmd, &output, &output)
+	return output.Bytes(), err
+}
+
+// lockedBuffer is a buffer that can be written by the stdout and stderr of a command at once.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Bytes()
+}
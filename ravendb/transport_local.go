@@ -0,0 +1,56 @@
+package ravendb
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+)
+
+const (
+	CONNECTION_SSH   string = "ssh"
+	CONNECTION_LOCAL string = "local"
+)
+
+// localTransport runs the commands on the machine running Terraform.
+type localTransport struct {
+	// sudoless is set when running as root on a machine without sudo, e.g. while building a container image
+	sudoless bool
+}
+
+func dialLocal(publicIP string, timeout time.Duration) (Transport, error) {
+	_, err := exec.LookPath("sudo")
+	return &localTransport{sudoless: err != nil && os.Geteuid() == 0}, nil
+}
+
+func (t *localTransport) Run(cmd string, stdout io.Writer, stderr io.Writer) error {
+	return t.run(cmd, nil, stdout, stderr)
+}
+
+func (t *localTransport) Upload(path string, content io.Reader, size int64, mode string) error {
+	var output bytes.Buffer
+	cmd := "sudo sh -c \"[ -e '" + path + "' ] || install -m " + mode + " /dev/null '" + path + "'; cat > '" + path + "'\""
+	err := t.run(cmd, io.LimitReader(content, size), &output, &output)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, output.Bytes())
+	}
+	return nil
+}
+
+func (t *localTransport) Close() error {
+	return nil
+}
+
+func (t *localTransport) run(cmd string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	if t.sudoless {
+		// the commands are written for a sudoer, a function makes root run them as they are
+		cmd = "sudo() { \"$@\"; }; " + cmd
+	}
+	command := exec.Command("sh", "-c", cmd)
+	command.Stdin = stdin
+	command.Stdout = stdout
+	command.Stderr = stderr
+	return command.Run()
+}
@@ -0,0 +1,157 @@
+package ravendb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sshTransport reaches a host over SSH, files are written with the scp protocol.
+type sshTransport struct {
+	client *ssh.Client
+}
+
+func (s *SSH) dial(publicIP string, timeout time.Duration) (Transport, error) {
+	signer, err := ssh.ParsePrivateKey(s.Pem)
+	if err != nil {
+		return nil, err
+	}
+	hostKeyCallback, err := s.hostKeyCallback()
+	if err != nil {
+		return nil, err
+	}
+	authConfig := &ssh.ClientConfig{
+		User:              s.User,
+		Auth:              []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback:   hostKeyCallback,
+		HostKeyAlgorithms: s.hostKeyAlgorithms(publicIP),
+		Timeout:           timeout,
+	}
+	client, err := ssh.Dial("tcp", net.JoinHostPort(publicIP, fmt.Sprint(s.getPort())), authConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &sshTransport{client: client}, nil
+}
+
+// hostKeyCallback checks the key of a host against its key in HostKeys, or else against KnownHostsFile. Host keys
+// are not checked when neither is set.
+func (s *SSH) hostKeyCallback() (ssh.HostKeyCallback, error) {
+	if s.KnownHostsFile == "" && len(s.HostKeys) == 0 {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+	var knownHosts ssh.HostKeyCallback
+	if s.KnownHostsFile != "" {
+		var err error
+		knownHosts, err = knownhosts.New(s.KnownHostsFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		host, _, err := net.SplitHostPort(hostname)
+		if err != nil {
+			host = hostname
+		}
+		if expected, ok := s.HostKeys[host]; ok {
+			return checkHostKey(host, expected, key)
+		}
+		if knownHosts != nil {
+			return knownHosts(hostname, remote, key)
+		}
+		return errors.New("no host key is pinned for " + host)
+	}, nil
+}
+
+// hostKeyAlgorithms returns the algorithm of the public key pinned for host, so the host presents that key rather
+// than another one it has. Fingerprints don't tell the algorithm, the default ones are used with them.
+func (s *SSH) hostKeyAlgorithms(host string) []string {
+	if key, err := parseHostKey(s.HostKeys[host]); err == nil && key != nil {
+		return []string{key.Type()}
+	}
+	return nil
+}
+
+// parseHostKey parses a public key in the authorized_keys format, e.g. ssh-ed25519 AAAA..., and returns nil for a
+// SHA256 fingerprint.
+func parseHostKey(hostKey string) (ssh.PublicKey, error) {
+	if strings.HasPrefix(hostKey, "SHA256:") {
+		return nil, nil
+	}
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(hostKey))
+	return key, err
+}
+
+func checkHostKey(host string, expected string, key ssh.PublicKey) error {
+	pinned, err := parseHostKey(expected)
+	if err != nil {
+		return errors.New("invalid host key pinned for " + host + ": " + err.Error())
+	}
+	if pinned == nil {
+		if ssh.FingerprintSHA256(key) == expected {
+			return nil
+		}
+	} else if bytes.Equal(pinned.Marshal(), key.Marshal()) {
+		return nil
+	}
+	return errors.New("the " + key.Type() + " host key of " + host + ", " + ssh.FingerprintSHA256(key) + ", doesn't match the pinned one")
+}
+
+// validateHostKeys checks that every value of a host_keys map is a public key or a SHA256 fingerprint.
+func validateHostKeys(v interface{}, k string) ([]string, []error) {
+	var errs []error
+	for host, hostKey := range v.(map[string]interface{}) {
+		if _, err := parseHostKey(hostKey.(string)); err != nil {
+			errs = append(errs, fmt.Errorf("%s: the key of %s is neither a public key nor a SHA256 fingerprint: %s", k, host, err.Error()))
+		}
+	}
+	return nil, errs
+}
+
+func (t *sshTransport) Run(cmd string, stdout io.Writer, stderr io.Writer) error {
+	session, err := t.client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+	session.Stdout = stdout
+	session.Stderr = stderr
+	return session.Run(cmd)
+}
+
+func (t *sshTransport) Upload(path string, content io.Reader, size int64, mode string) error {
+	//https://chuacw.ath.cx/development/b/chuacw/archive/2019/02/04/how-the-scp-protocol-works.aspx
+	session, err := t.client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return err
+	}
+	go func() {
+		defer stdin.Close()
+		fmt.Fprint(stdin, "C"+mode+" "+strconv.FormatInt(size, 10)+" file\n")
+		io.CopyN(stdin, content, size)
+		fmt.Fprint(stdin, "\x00")
+	}()
+
+	output, err := session.CombinedOutput("sudo scp -t " + path)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, output)
+	}
+	return nil
+}
+
+func (t *sshTransport) Close() error {
+	return t.client.Close()
+}
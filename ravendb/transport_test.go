@@ -0,0 +1,158 @@
+package ravendb
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"golang.org/x/crypto/ssh"
+	"io"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeTransport answers commands from outputs and records what was run and uploaded on it. Commands missing from
+// outputs succeed without output, the ones in failures fail with their output.
+type fakeTransport struct {
+	outputs  map[string]string
+	failures map[string]string
+	commands []string
+	uploads  map[string]string
+	closed   bool
+}
+
+func newFakeTransport() *fakeTransport {
+	return &fakeTransport{
+		outputs:  map[string]string{},
+		failures: map[string]string{},
+		uploads:  map[string]string{},
+	}
+}
+
+func (t *fakeTransport) Run(cmd string, stdout io.Writer, stderr io.Writer) error {
+	t.commands = append(t.commands, cmd)
+	if output, ok := t.failures[cmd]; ok {
+		io.WriteString(stderr, output)
+		return errors.New("exit status 1")
+	}
+	io.WriteString(stdout, t.outputs[cmd])
+	return nil
+}
+
+func (t *fakeTransport) Upload(path string, content io.Reader, size int64, mode string) error {
+	data, err := ioutil.ReadAll(io.LimitReader(content, size))
+	if err != nil {
+		return err
+	}
+	t.uploads[path] = mode + " " + string(data)
+	return nil
+}
+
+func (t *fakeTransport) Close() error {
+	t.closed = true
+	return nil
+}
+
+func (t *fakeTransport) dialer() dialer {
+	return func(publicIP string, timeout time.Duration) (Transport, error) {
+		return t, nil
+	}
+}
+
+func TestOnHostRunsStepsThroughTransport(t *testing.T) {
+	transport := newFakeTransport()
+	sc := &ServerConfig{dial: transport.dialer()}
+
+	err := sc.onHost("10.0.0.1",
+		sc.command("10.0.0.1", "sudo systemctl restart ravendb"),
+		func(conn Transport, stdoutBuf *nodeLog) error {
+			return uploadWithPermissions(conn, stdoutBuf, "/usr/local/bin/backup.sh", []byte("#!/bin/sh"), "0750", "root", "root")
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"sudo systemctl restart ravendb",
+		"sudo chown root:root /usr/local/bin/backup.sh && sudo chmod 0750 /usr/local/bin/backup.sh",
+	}
+	if strings.Join(transport.commands, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected the commands %q, got %q", expected, transport.commands)
+	}
+	if transport.uploads["/usr/local/bin/backup.sh"] != "0750 #!/bin/sh" {
+		t.Errorf("unexpected upload %q", transport.uploads["/usr/local/bin/backup.sh"])
+	}
+	if !transport.closed {
+		t.Error("expected the transport to be closed")
+	}
+}
+
+func TestOnHostStopsAtFailedStep(t *testing.T) {
+	transport := newFakeTransport()
+	transport.failures["false"] = "failed"
+	sc := &ServerConfig{dial: transport.dialer()}
+
+	err := sc.onHost("10.0.0.1", sc.command("10.0.0.1", "false"), sc.command("10.0.0.1", "true"))
+	var deployErr *DeployError
+	if !errors.As(err, &deployErr) || !strings.Contains(deployErr.Output, "failed") {
+		t.Fatalf("expected a deploy error with the output of the command, got %v", err)
+	}
+	if len(transport.commands) != 1 {
+		t.Errorf("expected the steps after the failed one to be skipped, ran %q", transport.commands)
+	}
+}
+
+func TestPreflightCheck(t *testing.T) {
+	transport := newFakeTransport()
+	transport.failures["test -d /run/systemd/system"] = ""
+	transport.outputs["awk '/^MemTotal:/ { print int($2 / 1024) }' /proc/meminfo"] = "1024\n"
+
+	failures := (&Preflight{MinMemoryMb: 2048}).check(transport, nil)
+	expected := []string{"systemd is not running", "1024 MB of memory, 2048 MB required"}
+	if strings.Join(failures, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected the failures %q, got %q", expected, failures)
+	}
+}
+
+func TestHostKeyCallback(t *testing.T) {
+	newKey := func() ssh.PublicKey {
+		public, _, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := ssh.NewPublicKey(public)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key
+	}
+	pinned, fingerprinted, other := newKey(), newKey(), newKey()
+	s := SSH{HostKeys: map[string]string{
+		"10.0.0.1":         string(ssh.MarshalAuthorizedKey(pinned)),
+		"db-b.example.com": ssh.FingerprintSHA256(fingerprinted),
+	}}
+	callback, err := s.hostKeyCallback()
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 22}
+
+	if err := callback("10.0.0.1:22", remote, pinned); err != nil {
+		t.Errorf("expected the pinned key to be accepted: %v", err)
+	}
+	if err := callback("db-b.example.com:22", remote, fingerprinted); err != nil {
+		t.Errorf("expected the key matching the fingerprint to be accepted: %v", err)
+	}
+	if err := callback("10.0.0.1:22", remote, other); err == nil {
+		t.Error("expected another key to be rejected")
+	}
+	if err := callback("10.0.0.3:22", remote, other); err == nil {
+		t.Error("expected a host without a pinned key to be rejected")
+	}
+	if algorithms := s.hostKeyAlgorithms("10.0.0.1"); len(algorithms) != 1 || algorithms[0] != ssh.KeyAlgoED25519 {
+		t.Errorf("expected the host to be asked for its ed25519 key, got %v", algorithms)
+	}
+}
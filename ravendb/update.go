@@ -0,0 +1,119 @@
+package ravendb
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"reflect"
+)
+
+// installAttributes are the attributes of ravendb_server that take part in preparing the hosts and installing
+// RavenDB on them. A change to any of them runs the whole deploy again.
+var installAttributes = []string{
+	"hosts",
+	"node",
+	"package",
+	"resolved_version",
+	"deploy_mode",
+	"preflight",
+	"unattended_upgrades",
+	"clock_sync",
+	"manage_hostname",
+	"peer_hosts",
+}
+
+// clusterAttributes are the attributes of ravendb_server applied through the cluster alone, or not applied at
+// all, so changing them doesn't touch the nodes.
+var clusterAttributes = map[string]bool{
+	"database":                  true,
+	"healthcheck_database":      true,
+	"parallel":                  true,
+	"readiness":                 true,
+	"postgresql":                true,
+	"consul":                    true,
+	"webhook":                   true,
+	"client_certificates":       true,
+	"databases":                 true,
+	"ignore_embedded_databases": true,
+	"hard_delete_databases":     true,
+	"deploy_retry":              true,
+	"rollback_on_failure":       true,
+	"debug_bundle_directory":    true,
+	"connection":                true,
+	"ssh":                       true,
+}
+
+// nodeConfigurationChanged reports whether an attribute written to the nodes changed, that is any attribute
+// set in the configuration but the cluster ones.
+func nodeConfigurationChanged(d *schema.ResourceData) bool {
+	for key, s := range resourceRavendbServer().Schema {
+		if clusterAttributes[key] || (s.Computed && !s.Optional) {
+			continue
+		}
+		if d.HasChange(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// changedDatabases returns the databases whose block changed. All of them are changed when the hosts change, as
+// the replication factor defaults to their number, or when the databases block was ignored before. There are
+// none while it is ignored.
+func changedDatabases(d *schema.ResourceData, databases []Database) []Database {
+	if len(databases) == 0 {
+		return nil
+	}
+	if d.HasChanges("hosts", "node", "ignore_embedded_databases") {
+		return databases
+	}
+	before, _ := d.GetChange("databases")
+	previous := map[string]interface{}{}
+	for _, v := range before.([]interface{}) {
+		previous[v.(map[string]interface{})["name"].(string)] = v
+	}
+
+	var changed []Database
+	for i, v := range d.Get("databases").([]interface{}) {
+		value := v.(map[string]interface{})
+		if !reflect.DeepEqual(previous[value["name"].(string)], v) {
+			changed = append(changed, databases[i])
+		}
+	}
+	return changed
+}
+
+// update applies a change to a deployed cluster. Unlike Deploy, it only installs RavenDB again when the hosts or
+// the package changed, only configures the nodes when their configuration changed, and only reconfigures the
+// databases whose block changed. The nodes themselves only get the files whose content changed.
+func (sc *ServerConfig) update(d *schema.ResourceData) error {
+	configured := false
+	if sc.DeployMode != DEPLOY_MODE_CLUSTER_ONLY {
+		var err error
+		if d.HasChanges(installAttributes...) {
+			configured = true
+			err = sc.deployRavenDbInstances()
+		} else if nodeConfigurationChanged(d) || d.HasChange("outdated_certificates") {
+			configured = true
+			err = sc.configureNodes()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if configured {
+		err := sc.report.timed("wait_for_nodes", sc.waitForNodes)
+		if err != nil {
+			return err
+		}
+	}
+
+	cluster := *sc
+	cluster.Databases = changedDatabases(d, sc.Databases)
+	err := sc.report.timed("configure_cluster", func() error {
+		_, err := cluster.configureCluster()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return sc.report.timed("wait_for_cluster", sc.waitForCluster)
+}
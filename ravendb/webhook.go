@@ -0,0 +1,168 @@
+package ravendb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"io/ioutil"
+	"net/http"
+	"text/template"
+	"time"
+)
+
+const (
+	WEBHOOK_ACTION_CREATE  string = "create"
+	WEBHOOK_ACTION_UPDATE  string = "update"
+	WEBHOOK_ACTION_DESTROY string = "destroy"
+)
+
+type Webhook struct {
+	Url         string
+	Template    string
+	ContentType string
+	Headers     map[string]string
+}
+
+// webhookEvent is the payload of the webhook, and the data its template is executed with.
+type webhookEvent struct {
+	Action string        `json:"action"`
+	Id     string        `json:"id"`
+	Nodes  []webhookNode `json:"nodes"`
+}
+
+type webhookNode struct {
+	Host    string `json:"host"`
+	Url     string `json:"url"`
+	Version string `json:"version"`
+}
+
+func webhookSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Posts the nodes of the cluster, their urls and versions to a url after every successful create, update and destroy.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"url": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				},
+				"template": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "A Go template of the request body, executed with .Action, .Id and .Nodes (.Host, .Url and .Version). The event is posted as JSON when unset.",
+					ValidateFunc: func(i interface{}, k string) ([]string, []error) {
+						if _, err := template.New(k).Parse(i.(string)); err != nil {
+							return nil, []error{err}
+						}
+						return nil, nil
+					},
+				},
+				"content_type": {
+					Type:     schema.TypeString,
+					Optional: true,
+					Default:  "application/json",
+				},
+				"headers": {
+					Type:        schema.TypeMap,
+					Optional:    true,
+					Sensitive:   true,
+					Description: "Extra request headers, e.g. an Authorization header.",
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+			},
+		},
+	}
+}
+
+func parseWebhook(d *schema.ResourceData) *Webhook {
+	for _, v := range d.Get("webhook").(*schema.Set).List() {
+		value := v.(map[string]interface{})
+		headers := make(map[string]string)
+		for name, header := range value["headers"].(map[string]interface{}) {
+			headers[name] = header.(string)
+		}
+		return &Webhook{
+			Url:         value["url"].(string),
+			Template:    value["template"].(string),
+			ContentType: value["content_type"].(string),
+			Headers:     headers,
+		}
+	}
+	return nil
+}
+
+// notify posts the event of action to the webhook. The versions of the nodes are taken from the nodes attribute
+// of d. A failed notification is reported as a warning, as the change it reports was applied.
+func (w *Webhook) notify(d *schema.ResourceData, sc ServerConfig, action string) diag.Diagnostics {
+	if w == nil {
+		return nil
+	}
+	versions := make(map[string]string)
+	for _, node := range d.Get("nodes").([]interface{}) {
+		if node != nil {
+			values := node.(map[string]interface{})
+			versions[values["host"].(string)] = values["version"].(string)
+		}
+	}
+	event := webhookEvent{Action: action, Id: d.Id(), Nodes: []webhookNode{}}
+	for index, host := range sc.Hosts {
+		event.Nodes = append(event.Nodes, webhookNode{Host: host, Url: sc.Url.List[index], Version: versions[host]})
+	}
+
+	err := w.send(event)
+	if err != nil {
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "The " + action + " webhook notification failed",
+			Detail:   err.Error(),
+		}}
+	}
+	return nil
+}
+
+func (w *Webhook) send(event webhookEvent) error {
+	var body bytes.Buffer
+	if w.Template == "" {
+		err := json.NewEncoder(&body).Encode(event)
+		if err != nil {
+			return err
+		}
+	} else {
+		tmpl, err := template.New("webhook").Parse(w.Template)
+		if err != nil {
+			return err
+		}
+		err = tmpl.Execute(&body, event)
+		if err != nil {
+			return err
+		}
+	}
+
+	request, err := http.NewRequest(http.MethodPost, w.Url, &body)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", w.ContentType)
+	for name, value := range w.Headers {
+		request.Header.Set(name, value)
+	}
+	client := http.Client{Timeout: 30 * time.Second}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		output, _ := ioutil.ReadAll(response.Body)
+		return errors.New("webhook request failed with HTTP status code: " + response.Status + "\n" + string(output))
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ package utils
 //#cgo windows LDFLAGS: "-LC:/Program Files/OpenSSL-Win64/lib" -llibcrypto
 //#cgo linux LDFLAGS: -lssl -lcrypto
 //#cgo CFLAGS: -Wno-deprecated-declarations
+// #include <stdlib.h>
 // #include "pfx.h"
 import "C"
 
@@ -36,9 +37,20 @@ func errorFromErrorQueue() string {
 }
 
 func PfxToPem(pfx []byte) (keyBuf []byte, crtBuf []byte, err error) {
+	return pfxToPem(pfx, nil)
+}
+
+// PfxToPemWithPassword converts a pfx protected by password.
+func PfxToPemWithPassword(pfx []byte, password string) (keyBuf []byte, crtBuf []byte, err error) {
+	pwd := C.CString(password)
+	defer C.free(unsafe.Pointer(pwd))
+	return pfxToPem(pfx, pwd)
+}
+
+func pfxToPem(pfx []byte, pwd *C.char) (keyBuf []byte, crtBuf []byte, err error) {
 	var key *C.void
 	var crt *C.void
-	rc := C.pfx_to_pem(unsafe.Pointer(&pfx[0]), C.long(len(pfx)), nil,
+	rc := C.pfx_to_pem(unsafe.Pointer(&pfx[0]), C.long(len(pfx)), pwd,
 		(*unsafe.Pointer)(unsafe.Pointer(&key)),
 		(*unsafe.Pointer)(unsafe.Pointer(&crt)))
 